@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/never00rei/a7/journal"
+)
+
+var errAttachUsage = errors.New("usage: a7 attach <note> <file>")
+
+func runAttach(args []string, out io.Writer) error {
+	if len(args) != 2 {
+		return errAttachUsage
+	}
+
+	conf, err := loadConf()
+	if err != nil {
+		return err
+	}
+	svc := journal.NewService(conf.JournalPath, journal.WithEncryption(conf.Encrypt, conf.SshKeyFile), journal.WithHooks(hookRunner()))
+	filename, err := findNote(svc, args[0])
+	if err != nil {
+		return err
+	}
+	note, err := svc.LoadNote(filename)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := svc.AddAttachment(filename, args[1])
+	if err != nil {
+		return err
+	}
+	body := strings.TrimRight(note.Content, "\n") + "\n\n" + attachment.Link() + "\n"
+	if err := svc.UpdateNote(filename, note.Title, body, note.Created); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Attached %s to %s\n", attachment.Name, filename)
+	return nil
+}
+
+// findNote resolves a note argument given as a filename, with or without
+// the .md extension.
+func findNote(svc *journal.Service, name string) (string, error) {
+	notes, err := svc.ListNotes()
+	if err != nil {
+		return "", err
+	}
+	id := journal.NoteID(name)
+	for _, note := range notes {
+		if journal.NoteID(note.Filename) == id {
+			return note.Filename, nil
+		}
+	}
+	return "", fmt.Errorf("no note named %q", name)
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/never00rei/a7/config"
+	"github.com/never00rei/a7/journal/hooks"
+	"github.com/never00rei/a7/ui/app"
+)
+
+var ErrNotConfigured = errors.New("a7 is not set up yet, run a7 to complete setup")
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string, out io.Writer) error
+}
+
+func commands() []command {
+	return []command{
+		{name: "new", usage: "new [--template name]", summary: "Open the editor for a new journal entry", run: runNew},
+		{name: "tasks", usage: "tasks [--open]", summary: "List checklist items from all entries", run: runTasks},
+		{name: "stats", usage: "stats [--json]", summary: "Show writing streaks and totals", run: runStats},
+		{name: "export", usage: "export html|json|epub|markdown <target>", summary: "Export the journal as a website or a single-file archive", run: runExport},
+		{name: "import", usage: "import dayone|jrnl|markdown <path>", summary: "Import entries from Day One, jrnl or a Markdown folder", run: runImport},
+		{name: "migrate", usage: "migrate [--dry-run]", summary: "Convert legacy header notes to front matter", run: runMigrate},
+		{name: "attach", usage: "attach <note> <file>", summary: "Copy a file into a note's attachments and link it", run: runAttach},
+		{name: "delete", usage: "delete <note>", summary: "Move a note and its attachments to the journal backup folder", run: runDelete},
+		{name: "unlock", usage: "unlock", summary: "Clear journal locks left by an a7 that did not exit cleanly", run: runUnlock},
+		{name: "serve", usage: "serve [--addr host:port]", summary: "Serve the journal read-only to a browser and JSON clients", run: runServe},
+		{name: "rpc", usage: "rpc", summary: "Answer JSON-RPC requests on stdin for editor plugins", run: runRPC},
+	}
+}
+
+func Run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return runTUI()
+	}
+
+	name := args[0]
+	switch name {
+	case "help", "-h", "--help":
+		printUsage(out)
+		return nil
+	}
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			err := cmd.run(args[1:], out)
+			if errors.Is(err, flag.ErrHelp) {
+				// The flag set has already printed the command's usage.
+				return nil
+			}
+			return err
+		}
+	}
+	printUsage(out)
+	return fmt.Errorf("unknown command %q", name)
+}
+
+func runTUI(opts ...app.Option) error {
+	final, err := tea.NewProgram(app.NewAppModel(opts...), tea.WithAltScreen()).Run()
+	if model, ok := final.(app.AppModel); ok {
+		model.Cleanup()
+	}
+	return err
+}
+
+func loadConf() (*config.Conf, error) {
+	conf, err := config.LoadConf()
+	if err != nil || conf.JournalPath == "" {
+		return nil, ErrNotConfigured
+	}
+	return conf, nil
+}
+
+// hookRunner runs hooks from the config folder, printing failures of hooks
+// that run after a change to stderr so they never mix with command output.
+func hookRunner() *hooks.Runner {
+	dir, err := config.BuildHooksPath(config.Home, config.XdgConfigHome)
+	if err != nil {
+		return nil
+	}
+	runner := hooks.NewRunner(dir)
+	runner.Report = func(err error) {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return runner
+}
+
+func printUsage(out io.Writer) {
+	lines := []string{"Usage:", "  a7                 Start the terminal journal"}
+	for _, cmd := range commands() {
+		lines = append(lines, fmt.Sprintf("  a7 %-16s %s", cmd.usage, cmd.summary))
+	}
+	fmt.Fprintln(out, strings.Join(lines, "\n"))
+}
+
+// reorderFlags moves flags ahead of positional arguments so commands accept
+// both "export html dir --flag" and "export html --flag dir".
+func reorderFlags(set *flag.FlagSet, args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+		flags = append(flags, arg)
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if f := set.Lookup(name); f != nil && !isBoolFlag(f) && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+	return append(flags, positional...)
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
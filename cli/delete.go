@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/never00rei/a7/journal"
+)
+
+var errDeleteUsage = errors.New("usage: a7 delete <note>")
+
+func runDelete(args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errDeleteUsage
+	}
+	conf, err := loadConf()
+	if err != nil {
+		return err
+	}
+	svc := journal.NewService(conf.JournalPath, journal.WithEncryption(conf.Encrypt, conf.SshKeyFile), journal.WithHooks(hookRunner()))
+	filename, err := findNote(svc, args[0])
+	if err != nil {
+		return err
+	}
+	backup, err := svc.DeleteNote(filename)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Deleted %s, moved to %s\n", filename, backup)
+	return nil
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/export"
+)
+
+const exportDateLayout = "2006-01-02"
+
+var errExportUsage = errors.New("usage: a7 export html|json|epub|markdown <target> [--from YYYY-MM-DD] [--to YYYY-MM-DD] [--tag tag]... [--exclude-encrypted] [--skip-encrypted] [--title title]")
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+func runExport(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errExportUsage
+	}
+	format := args[0]
+
+	flags := flag.NewFlagSet("export "+format, flag.ContinueOnError)
+	flags.SetOutput(out)
+	var tags stringList
+	flags.Var(&tags, "tag", "only export entries with this tag (repeatable)")
+	from := flags.String("from", "", "only export entries on or after this date (YYYY-MM-DD)")
+	to := flags.String("to", "", "only export entries on or before this date (YYYY-MM-DD)")
+	title := flags.String("title", "", "title of the exported journal")
+	excludeEncrypted := flags.Bool("exclude-encrypted", false, "leave out all encrypted entries")
+	skipEncrypted := flags.Bool("skip-encrypted", false, "leave out encrypted entries that cannot be decrypted")
+	if err := flags.Parse(reorderFlags(flags, args[1:])); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errExportUsage
+	}
+	target := flags.Arg(0)
+
+	opts := export.Options{
+		Tags:             tags,
+		ExcludeEncrypted: *excludeEncrypted,
+		SkipEncrypted:    *skipEncrypted,
+		Title:            *title,
+	}
+	var err error
+	if opts.From, err = parseExportDate("from", *from); err != nil {
+		return err
+	}
+	if opts.To, err = parseExportDate("to", *to); err != nil {
+		return err
+	}
+
+	conf, err := loadConf()
+	if err != nil {
+		return err
+	}
+	svc := journal.NewService(conf.JournalPath, journal.WithEncryption(conf.Encrypt, conf.SshKeyFile))
+
+	var report export.Report
+	switch format {
+	case "html":
+		report, err = export.HTML(svc, target, opts)
+	case "json":
+		report, err = exportToFile(target, out, func(w io.Writer) (export.Report, error) {
+			return export.JSON(svc, w, opts)
+		})
+	case "epub":
+		report, err = exportToFile(target, out, func(w io.Writer) (export.Report, error) {
+			return export.EPUB(svc, w, opts)
+		})
+	case "markdown", "md":
+		report, err = exportToFile(target, out, func(w io.Writer) (export.Report, error) {
+			return export.Markdown(svc, w, opts)
+		})
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keep stdout clean when the export itself was written there.
+	if target == "-" {
+		return nil
+	}
+	fmt.Fprintf(out, "Exported %d entries to %s", report.Written, target)
+	if report.Locked > 0 {
+		fmt.Fprintf(out, " (%d encrypted entries without content)", report.Locked)
+	}
+	if report.Skipped > 0 {
+		fmt.Fprintf(out, " (%d encrypted entries skipped)", report.Skipped)
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+func parseExportDate(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.ParseInLocation(exportDateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s date %q, want YYYY-MM-DD", name, value)
+	}
+	return date, nil
+}
+
+func exportToFile(target string, stdout io.Writer, write func(io.Writer) (export.Report, error)) (export.Report, error) {
+	if target == "-" {
+		return write(stdout)
+	}
+	file, err := os.Create(target)
+	if err != nil {
+		return export.Report{}, fmt.Errorf("create %s: %w", target, err)
+	}
+	report, err := write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return report, err
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/importer"
+)
+
+var errImportUsage = errors.New("usage: a7 import dayone|jrnl|markdown <path> [--dry-run]")
+
+func runImport(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errImportUsage
+	}
+	format := args[0]
+
+	flags := flag.NewFlagSet("import "+format, flag.ContinueOnError)
+	flags.SetOutput(out)
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without writing notes")
+	if err := flags.Parse(reorderFlags(flags, args[1:])); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errImportUsage
+	}
+	source := flags.Arg(0)
+
+	var entries []importer.Entry
+	var err error
+	switch format {
+	case "dayone":
+		entries, err = importer.DayOne(source)
+	case "jrnl":
+		entries, err = importer.Jrnl(source)
+	case "markdown", "md", "obsidian":
+		entries, err = importer.MarkdownDir(source)
+	default:
+		return fmt.Errorf("unknown import format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	conf, err := loadConf()
+	if err != nil {
+		return err
+	}
+	svc := journal.NewService(conf.JournalPath, journal.WithEncryption(conf.Encrypt, conf.SshKeyFile), journal.WithHooks(hookRunner()))
+	report, err := importer.Import(svc, entries, importer.Options{DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Would import"
+	}
+	fmt.Fprintf(out, "%s %d entries, %d duplicates, %d skipped\n", verb, len(report.Imported), len(report.Duplicates), len(report.Skipped))
+	for _, source := range report.Duplicates {
+		fmt.Fprintf(out, "  duplicate: %s\n", source)
+	}
+	for _, skipped := range report.Skipped {
+		fmt.Fprintf(out, "  skipped:   %s (%s)\n", skipped.Source, skipped.Reason)
+	}
+	return nil
+}
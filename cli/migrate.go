@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/never00rei/a7/journal"
+)
+
+func runMigrate(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(out)
+	dryRun := flags.Bool("dry-run", false, "list legacy notes without rewriting them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	conf, err := loadConf()
+	if err != nil {
+		return err
+	}
+	svc := journal.NewService(conf.JournalPath, journal.WithEncryption(conf.Encrypt, conf.SshKeyFile))
+	report, err := svc.MigrateLegacy(*dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, note := range report.Migrated {
+		fmt.Fprintf(out, "  %s: %q, %d words\n", note.Filename, note.Title, note.WordCount)
+	}
+	switch {
+	case len(report.Migrated) == 0:
+		fmt.Fprintf(out, "Scanned %d notes, none use the legacy format\n", report.Scanned)
+	case *dryRun:
+		fmt.Fprintf(out, "Scanned %d notes, %d would be migrated\n", report.Scanned, len(report.Migrated))
+	default:
+		fmt.Fprintf(out, "Scanned %d notes, migrated %d. Originals are in %s\n", report.Scanned, len(report.Migrated), report.BackupDir)
+	}
+	return nil
+}
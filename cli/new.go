@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/never00rei/a7/config"
+	"github.com/never00rei/a7/journal/templates"
+	"github.com/never00rei/a7/ui/app"
+)
+
+func runNew(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	flags.SetOutput(out)
+	templateName := flags.String("template", "", "name of the template to start from")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if _, err := loadConf(); err != nil {
+		return err
+	}
+
+	var tmpl templates.Template
+	if *templateName != "" {
+		dir, err := config.BuildTemplatesPath(config.Home, config.XdgConfigHome)
+		if err != nil {
+			return err
+		}
+		if err := templates.Seed(dir); err != nil {
+			return err
+		}
+		available, err := templates.Load(dir)
+		if err != nil {
+			return err
+		}
+		found, ok := templates.Find(available, *templateName)
+		if !ok {
+			return fmt.Errorf("unknown template %q", *templateName)
+		}
+		tmpl = found
+	}
+
+	return runTUI(app.WithTemplate(tmpl))
+}
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/rpc"
+)
+
+var errRPCUsage = errors.New("usage: a7 rpc")
+
+func runRPC(args []string, out io.Writer) error {
+	if len(args) != 0 {
+		return errRPCUsage
+	}
+	conf, err := loadConf()
+	if err != nil {
+		return err
+	}
+	svc := journal.NewService(conf.JournalPath, journal.WithEncryption(conf.Encrypt, conf.SshKeyFile), journal.WithHooks(hookRunner()))
+	return rpc.New(svc).Serve(os.Stdin, out)
+}
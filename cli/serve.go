@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/server"
+)
+
+var errServeUsage = errors.New("usage: a7 serve [--addr host:port] [--token token] [--tls-cert file --tls-key file] [--no-decrypt] [--title title]")
+
+func runServe(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(out)
+	addr := flags.String("addr", "127.0.0.1:8080", "address to listen on")
+	token := flags.String("token", os.Getenv("A7_TOKEN"), "access token (default $A7_TOKEN, or a random one)")
+	certFile := flags.String("tls-cert", "", "TLS certificate file")
+	keyFile := flags.String("tls-key", "", "TLS private key file")
+	noDecrypt := flags.Bool("no-decrypt", false, "never decrypt encrypted entries, even with the key configured")
+	title := flags.String("title", "", "title shown on the index page")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errServeUsage
+	}
+	if (*certFile == "") != (*keyFile == "") {
+		return errors.New("--tls-cert and --tls-key must be used together")
+	}
+
+	conf, err := loadConf()
+	if err != nil {
+		return err
+	}
+	keyPath := conf.SshKeyFile
+	if *noDecrypt {
+		keyPath = ""
+	}
+	svc := journal.NewService(conf.JournalPath, journal.WithEncryption(conf.Encrypt, keyPath))
+	if *token == "" {
+		if *token, err = server.NewToken(); err != nil {
+			return err
+		}
+	}
+	tls := *certFile != ""
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           server.New(svc, server.Options{Title: *title, Token: *token, Secure: tls}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	scheme := "http"
+	if tls {
+		scheme = "https"
+	}
+	fmt.Fprintf(out, "Serving %s at %s://%s/?token=%s\n", conf.JournalPath, scheme, listener.Addr(), *token)
+	if !tls {
+		if host, _, _ := net.SplitHostPort(listener.Addr().String()); !net.ParseIP(host).IsLoopback() {
+			fmt.Fprintln(out, "Without --tls-cert the token and entries cross the network unencrypted")
+		}
+	}
+	fmt.Fprintln(out, "Press Ctrl+C to stop")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	if tls {
+		err = srv.ServeTLS(listener, *certFile, *keyFile)
+	} else {
+		err = srv.Serve(listener)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
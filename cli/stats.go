@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/stats"
+)
+
+func runStats(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	flags.SetOutput(out)
+	asJSON := flags.Bool("json", false, "print the statistics as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	conf, err := loadConf()
+	if err != nil {
+		return err
+	}
+	notes, err := journal.NewService(conf.JournalPath).ListNotes()
+	if err != nil {
+		return err
+	}
+	summary := stats.Compute(notes, time.Now())
+
+	if *asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summary)
+	}
+
+	fmt.Fprintf(out, "Entries:         %d\n", summary.TotalEntries)
+	fmt.Fprintf(out, "Words:           %d\n", summary.TotalWords)
+	fmt.Fprintf(out, "Average length:  %.0f words\n", summary.AverageWords)
+	fmt.Fprintf(out, "Current streak:  %d days\n", summary.CurrentStreak)
+	fmt.Fprintf(out, "Longest streak:  %d days\n", summary.LongestStreak)
+	if len(summary.TopTags) > 0 {
+		fmt.Fprintln(out, "Top tags:")
+		for _, tag := range summary.TopTags {
+			fmt.Fprintf(out, "  #%-20s %d\n", tag.Tag, tag.Count)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/never00rei/a7/journal"
+)
+
+func runTasks(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("tasks", flag.ContinueOnError)
+	flags.SetOutput(out)
+	openOnly := flags.Bool("open", false, "only list unchecked tasks")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	conf, err := loadConf()
+	if err != nil {
+		return err
+	}
+	svc := journal.NewService(conf.JournalPath, journal.WithEncryption(conf.Encrypt, conf.SshKeyFile))
+	tasks, err := svc.ListTasks()
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, task := range tasks {
+		if task.Done && *openOnly {
+			continue
+		}
+		mark := " "
+		if task.Done {
+			mark = "x"
+		}
+		source := task.Note.Title
+		if source == "" {
+			source = journal.NoteID(task.Note.Filename)
+		}
+		if date, ok := task.Note.Date(); ok {
+			source += ", " + date.Local().Format("2006-01-02")
+		}
+		fmt.Fprintf(out, "- [%s] %s (%s)\n", mark, task.Text, source)
+		count++
+	}
+	if count == 0 {
+		fmt.Fprintln(out, "No tasks found")
+	}
+	return nil
+}
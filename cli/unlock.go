@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/never00rei/a7/journal"
+)
+
+var errUnlockUsage = errors.New("usage: a7 unlock")
+
+func runUnlock(args []string, out io.Writer) error {
+	if len(args) != 0 {
+		return errUnlockUsage
+	}
+	conf, err := loadConf()
+	if err != nil {
+		return err
+	}
+	holder, err := journal.NewService(conf.JournalPath).UnlockJournal()
+	if err != nil {
+		return err
+	}
+	if holder.PID == 0 {
+		fmt.Fprintln(out, "Journal was not locked")
+		return nil
+	}
+	fmt.Fprintf(out, "Removed lock held by %s\n", holder)
+	return nil
+}
@@ -17,6 +17,10 @@ var (
 	XdgConfigHome string = os.Getenv("XDG_CONFIG_HOME")
 	AppConfDir    string = ".a7-journal"
 	ConfFileName  string = "conf.ini"
+	TemplatesDir  string = "templates"
+	PromptsDir    string = "prompts"
+	ThemesDir     string = "themes"
+	HooksDir      string = "hooks"
 	SshPath       string = filepath.Join(Home, ".ssh")
 
 	ErrHomeConfigEnvVarNotSetError error = errors.New("home and xdg_config_home are not set")
@@ -28,6 +32,12 @@ type Conf struct {
 	SshPubKey   string
 	FirstSetup  bool
 	Encrypt     bool
+	SortBy      string
+	SortDesc    bool
+	GroupBy     string
+	Theme       string
+	KeyPreset   string
+	Keys        map[string]string
 }
 
 func BuildConfPath(homeDir, xdgConfigHomeDir string) (string, error) {
@@ -45,12 +55,40 @@ func BuildConfPath(homeDir, xdgConfigHomeDir string) (string, error) {
 	return path, ErrHomeConfigEnvVarNotSetError
 }
 
+func BuildTemplatesPath(homeDir, xdgConfigHomeDir string) (string, error) {
+	return buildConfSubPath(homeDir, xdgConfigHomeDir, TemplatesDir)
+}
+
+func BuildPromptsPath(homeDir, xdgConfigHomeDir string) (string, error) {
+	return buildConfSubPath(homeDir, xdgConfigHomeDir, PromptsDir)
+}
+
+func BuildThemesPath(homeDir, xdgConfigHomeDir string) (string, error) {
+	return buildConfSubPath(homeDir, xdgConfigHomeDir, ThemesDir)
+}
+
+func BuildHooksPath(homeDir, xdgConfigHomeDir string) (string, error) {
+	return buildConfSubPath(homeDir, xdgConfigHomeDir, HooksDir)
+}
+
+func buildConfSubPath(homeDir, xdgConfigHomeDir, name string) (string, error) {
+	confPath, err := BuildConfPath(homeDir, xdgConfigHomeDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(confPath, name), nil
+}
+
 func NewConf(journalPath, sshKeyPath, sshPubKey string, encrypt bool) *Conf {
 	return &Conf{
 		JournalPath: journalPath,
 		SshKeyFile:  sshKeyPath,
 		SshPubKey:   sshPubKey,
 		Encrypt:     encrypt,
+		SortBy:      "updated",
+		SortDesc:    true,
+		GroupBy:     "none",
+		Theme:       "auto",
 	}
 }
 
@@ -131,6 +169,27 @@ func (c *Conf) SaveConfig() error {
 		return err
 	}
 
+	if _, err = section.NewKey("theme", c.Theme); err != nil {
+		return err
+	}
+
+	dashboard, err := conf.NewSection("Dashboard")
+	if err != nil {
+		return err
+	}
+
+	if _, err = dashboard.NewKey("sort_by", c.SortBy); err != nil {
+		return err
+	}
+
+	if _, err = dashboard.NewKey("sort_desc", fmt.Sprintf("%t", c.SortDesc)); err != nil {
+		return err
+	}
+
+	if _, err = dashboard.NewKey("group_by", c.GroupBy); err != nil {
+		return err
+	}
+
 	if err = conf.SaveTo(confFilePath); err != nil {
 		return err
 	}
@@ -167,6 +226,25 @@ func LoadConf() (*Conf, error) {
 	encrypt := section.Key("encrypt").MustBool(false)
 
 	conf := NewConf(journalPath, sshKeyPath, sshPubKey, encrypt)
+	conf.Theme = section.Key("theme").MustString(conf.Theme)
+
+	if dashboard, err := confFile.GetSection("Dashboard"); err == nil {
+		conf.SortBy = dashboard.Key("sort_by").MustString(conf.SortBy)
+		conf.SortDesc = dashboard.Key("sort_desc").MustBool(conf.SortDesc)
+		conf.GroupBy = dashboard.Key("group_by").MustString(conf.GroupBy)
+	}
+
+	// The [Keys] section is edited by hand and left untouched by SaveConfig.
+	if keys, err := confFile.GetSection("Keys"); err == nil {
+		conf.Keys = make(map[string]string)
+		for _, key := range keys.Keys() {
+			if key.Name() == "preset" {
+				conf.KeyPreset = key.String()
+				continue
+			}
+			conf.Keys[key.Name()] = key.String()
+		}
+	}
 
 	return conf, nil
 }
@@ -62,3 +62,72 @@ func TestSaveConfigExistingDirNoFile(t *testing.T) {
 		t.Fatalf("encrypt = %v, want %v", got, conf.Encrypt)
 	}
 }
+
+func TestLoadConfReadsDashboardSettings(t *testing.T) {
+	tempDir := t.TempDir()
+
+	origHome := Home
+	origXdg := XdgConfigHome
+	t.Cleanup(func() {
+		Home = origHome
+		XdgConfigHome = origXdg
+	})
+
+	Home = tempDir
+	XdgConfigHome = ""
+
+	conf := NewConf(filepath.Join(tempDir, "journal"), "", "", false)
+	conf.SortBy = "title"
+	conf.SortDesc = false
+	conf.GroupBy = "month"
+	if err := conf.SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig error: %v", err)
+	}
+
+	loaded, err := LoadConf()
+	if err != nil {
+		t.Fatalf("LoadConf error: %v", err)
+	}
+	if loaded.SortBy != "title" || loaded.SortDesc || loaded.GroupBy != "month" {
+		t.Fatalf("dashboard settings = %q/%t/%q, want title/false/month", loaded.SortBy, loaded.SortDesc, loaded.GroupBy)
+	}
+}
+
+func TestLoadConfReadsKeysAndKeepsThemOnSave(t *testing.T) {
+	tempDir := t.TempDir()
+
+	origHome := Home
+	origXdg := XdgConfigHome
+	t.Cleanup(func() {
+		Home = origHome
+		XdgConfigHome = origXdg
+	})
+
+	Home = tempDir
+	XdgConfigHome = ""
+
+	conf := NewConf(filepath.Join(tempDir, "journal"), "", "", false)
+	if err := conf.SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig error: %v", err)
+	}
+	confFile := filepath.Join(tempDir, ".config", AppConfDir, ConfFileName)
+	f, err := os.OpenFile(confFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("open conf: %v", err)
+	}
+	if _, err := f.WriteString("\n[Keys]\npreset = vim\nsave = ctrl+w, ctrl+s\n"); err != nil {
+		t.Fatalf("write conf: %v", err)
+	}
+	f.Close()
+
+	if err := conf.SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig error: %v", err)
+	}
+	loaded, err := LoadConf()
+	if err != nil {
+		t.Fatalf("LoadConf error: %v", err)
+	}
+	if loaded.KeyPreset != "vim" || loaded.Keys["save"] != "ctrl+w, ctrl+s" {
+		t.Fatalf("keys = %q %v, want vim preset and save override", loaded.KeyPreset, loaded.Keys)
+	}
+}
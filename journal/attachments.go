@@ -0,0 +1,194 @@
+package journal
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/never00rei/a7/journal/codec"
+	"github.com/never00rei/a7/journal/crypto"
+)
+
+const (
+	AttachmentsDir         = "attachments"
+	encryptedAttachmentExt = ".age"
+)
+
+var imageExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".svg": true, ".heic": true,
+}
+
+// Attachment is a file stored under attachments/<note-id>/. Path is relative
+// to the journal root and uses forward slashes so it can be linked from
+// Markdown.
+type Attachment struct {
+	Name      string
+	Path      string
+	Size      int64
+	Encrypted bool
+}
+
+func NoteID(filename string) string {
+	return strings.TrimSuffix(filename, ".md")
+}
+
+func (a Attachment) IsImage() bool {
+	return imageExts[strings.ToLower(filepath.Ext(a.Name))]
+}
+
+// URL is the attachment path escaped for use as a Markdown link target.
+func (a Attachment) URL() string {
+	parts := strings.Split(a.Path, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// Link returns a Markdown link to the attachment, as an image when it is one
+// that renderers can show.
+func (a Attachment) Link() string {
+	if a.IsImage() && !a.Encrypted {
+		return fmt.Sprintf("![%s](%s)", a.Name, a.URL())
+	}
+	return fmt.Sprintf("[%s](%s)", a.Name, a.URL())
+}
+
+// AddAttachment copies src into the note's attachment folder, encrypting it
+// when the note is encrypted or encryption is turned on.
+func (s *Service) AddAttachment(noteFilename, src string) (Attachment, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("attach file: %w", err)
+	}
+	if info.IsDir() {
+		return Attachment{}, fmt.Errorf("attach file: %s is a directory", src)
+	}
+
+	encrypted := s.noteEncrypted(noteFilename)
+	dir := path.Join(AttachmentsDir, NoteID(noteFilename))
+	name := filepath.Base(src)
+	stored := name
+	if encrypted {
+		stored += encryptedAttachmentExt
+	}
+	for i := 1; s.store.Exists(path.Join(dir, stored)); i++ {
+		name = fmt.Sprintf("%d-%s", i, filepath.Base(src))
+		stored = name
+		if encrypted {
+			stored += encryptedAttachmentExt
+		}
+	}
+	attachment := Attachment{Name: name, Path: path.Join(dir, stored), Size: info.Size(), Encrypted: encrypted}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("attach file: %w", err)
+	}
+	defer in.Close()
+	out, err := s.store.CreateFile(attachment.Path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	if encrypted {
+		err = crypto.EncryptStream(out, in, s.SSHKeyPath)
+	} else {
+		_, err = io.Copy(out, in)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Attachment{}, fmt.Errorf("attach file: %w", err)
+	}
+	return attachment, nil
+}
+
+// PendingAttachment is a file to attach when a note is saved. Link is the
+// text in the body standing for it, replaced by the stored file's URL; a
+// file whose link is no longer in the body is left out.
+type PendingAttachment struct {
+	Source string
+	Link   string
+}
+
+func (s *Service) attachPending(filename, body string, pending []PendingAttachment) (string, error) {
+	for _, item := range pending {
+		if item.Link != "" && !strings.Contains(body, item.Link) {
+			continue
+		}
+		attachment, err := s.AddAttachment(filename, item.Source)
+		if err != nil {
+			return body, err
+		}
+		if item.Link != "" {
+			body = strings.ReplaceAll(body, item.Link, attachment.URL())
+		}
+	}
+	return body, nil
+}
+
+func (s *Service) ListAttachments(noteFilename string) ([]Attachment, error) {
+	dir := path.Join(AttachmentsDir, NoteID(noteFilename))
+	files, err := s.store.ListFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	attachments := make([]Attachment, 0, len(files))
+	for _, file := range files {
+		attachment := Attachment{Name: file.Name, Path: path.Join(dir, file.Name), Size: file.Size}
+		if strings.HasSuffix(file.Name, encryptedAttachmentExt) {
+			attachment.Name = strings.TrimSuffix(file.Name, encryptedAttachmentExt)
+			attachment.Encrypted = true
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// ExtractAttachment writes a readable copy of the attachment into dir,
+// decrypting it if needed, and returns the copy's path.
+func (s *Service) ExtractAttachment(attachment Attachment, dir string) (string, error) {
+	if !attachment.Encrypted {
+		return filepath.Join(s.Root, filepath.FromSlash(attachment.Path)), nil
+	}
+	in, err := s.store.OpenFile(attachment.Path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("create temp dir: %w", err)
+	}
+	target := filepath.Join(dir, attachment.Name)
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", fmt.Errorf("extract attachment: %w", err)
+	}
+	err = crypto.DecryptStream(out, in, s.SSHKeyPath)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(target)
+		return "", err
+	}
+	return target, nil
+}
+
+func (s *Service) noteEncrypted(noteFilename string) bool {
+	if s.Encrypt {
+		return true
+	}
+	content, _, err := s.store.Read(noteFilename)
+	if err != nil {
+		return false
+	}
+	matter, _ := codec.ParseFrontMatter(content)
+	return matter.Encrypted
+}
@@ -17,6 +17,8 @@ type FrontMatter struct {
 	Updated   time.Time
 	Encrypted bool
 	WordCount int
+	Prompt    string
+	Tags      []string
 }
 
 func BuildFilename(title string, created time.Time) string {
@@ -24,22 +26,23 @@ func BuildFilename(title string, created time.Time) string {
 	return fmt.Sprintf("%s_%s.md", created.Format(TimestampLayout), sanitizedTitle)
 }
 
-func RenderContent(title, body string, created, updated time.Time, encrypted bool, wordCount int) string {
-	return fmt.Sprintf(
-		"---\n"+
-			"title: %s\n"+
-			"created: %s\n"+
-			"updated: %s\n"+
-			"encrypted: %t\n"+
-			"word_count: %d\n"+
-			"---\n\n%s",
-		title,
-		created.Format(time.RFC3339),
-		updated.Format(time.RFC3339),
-		encrypted,
-		wordCount,
-		body,
-	)
+func RenderContent(matter FrontMatter, body string) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", matter.Title)
+	fmt.Fprintf(&b, "created: %s\n", matter.Created.Format(time.RFC3339))
+	fmt.Fprintf(&b, "updated: %s\n", matter.Updated.Format(time.RFC3339))
+	fmt.Fprintf(&b, "encrypted: %t\n", matter.Encrypted)
+	fmt.Fprintf(&b, "word_count: %d\n", matter.WordCount)
+	if matter.Prompt != "" {
+		fmt.Fprintf(&b, "prompt: %s\n", singleLine(matter.Prompt))
+	}
+	if len(matter.Tags) > 0 {
+		fmt.Fprintf(&b, "tags: %s\n", strings.Join(matter.Tags, ", "))
+	}
+	b.WriteString("---\n\n")
+	b.WriteString(body)
+	return b.String()
 }
 
 func ParseFrontMatter(content string) (FrontMatter, string) {
@@ -81,6 +84,10 @@ func ParseFrontMatter(content string) (FrontMatter, string) {
 			if parsed, err := strconv.Atoi(value); err == nil {
 				matter.WordCount = parsed
 			}
+		case "prompt":
+			matter.Prompt = value
+		case "tags":
+			matter.Tags = ParseTagList(value)
 		}
 	}
 	if end == -1 {
@@ -92,7 +99,14 @@ func ParseFrontMatter(content string) (FrontMatter, string) {
 	return matter, body
 }
 
+// ParseHeader reads the "# <timestamp> <title>" header of legacy notes. The
+// timestamp, like the one in filenames, is local wall-clock time.
 func ParseHeader(content string) (string, time.Time, string) {
+	return ParseHeaderIn(content, time.Local)
+}
+
+// ParseHeaderIn is ParseHeader with the header timestamp read in loc.
+func ParseHeaderIn(content string, loc *time.Location) (string, time.Time, string) {
 	lines := strings.Split(content, "\n")
 	if len(lines) == 0 {
 		return "", time.Time{}, content
@@ -109,7 +123,7 @@ func ParseHeader(content string) (string, time.Time, string) {
 		return "", time.Time{}, content
 	}
 
-	created, err := time.Parse(TimestampLayout, parts[0])
+	created, err := time.ParseInLocation(TimestampLayout, parts[0], loc)
 	if err != nil {
 		return strings.TrimSpace(parts[1]), time.Time{}, strings.Join(lines[1:], "\n")
 	}
@@ -119,6 +133,22 @@ func ParseHeader(content string) (string, time.Time, string) {
 	return strings.TrimSpace(parts[1]), created, body
 }
 
+func ParseFilenameTimestamp(filename string) (time.Time, bool) {
+	return ParseFilenameTimestampIn(filename, time.Local)
+}
+
+// ParseFilenameTimestampIn reads the timestamp a filename starts with in loc.
+func ParseFilenameTimestampIn(filename string, loc *time.Location) (time.Time, bool) {
+	if len(filename) < len(TimestampLayout) {
+		return time.Time{}, false
+	}
+	parsed, err := time.ParseInLocation(TimestampLayout, filename[:len(TimestampLayout)], loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
 func ParseTimestamp(value string) time.Time {
 	if value == "" {
 		return time.Time{}
@@ -136,3 +166,7 @@ func CountWords(content string) int {
 	fields := strings.Fields(content)
 	return len(fields)
 }
+
+func singleLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,33 @@
+package codec
+
+import (
+	"regexp"
+	"strings"
+)
+
+var headingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+
+// Heading is an ATX Markdown heading. Line is its zero-based line in the
+// note body and Level runs from 1 to 6.
+type Heading struct {
+	Line  int
+	Level int
+	Text  string
+}
+
+func ParseHeadings(body string) []Heading {
+	var headings []Heading
+	mapProseLines(body, func(index int, line string) string {
+		match := headingPattern.FindStringSubmatch(line)
+		if match == nil || strings.TrimSpace(match[2]) == "" {
+			return line
+		}
+		headings = append(headings, Heading{
+			Line:  index,
+			Level: len(match[1]),
+			Text:  strings.TrimSpace(match[2]),
+		})
+		return line
+	})
+	return headings
+}
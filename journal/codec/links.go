@@ -0,0 +1,57 @@
+package codec
+
+import (
+	"regexp"
+	"strings"
+)
+
+var wikiLinkPattern = regexp.MustCompile(`(!?)\[\[([^\[\]|#]+)(#[^\[\]|]*)?(\|[^\[\]]*)?\]\]`)
+
+// WikiLink is a [[Target#Heading|Alias]] reference to another note.
+type WikiLink struct {
+	Target  string
+	Heading string
+	Alias   string
+}
+
+// ParseWikiLinks returns the note links in body in order of appearance,
+// without duplicates. Embeds (![[file]]) and fenced code are ignored.
+func ParseWikiLinks(body string) []WikiLink {
+	var links []WikiLink
+	seen := map[string]bool{}
+	mapProseLines(body, func(_ int, line string) string {
+		for _, match := range wikiLinkPattern.FindAllStringSubmatch(line, -1) {
+			if match[1] == "!" {
+				continue
+			}
+			link := WikiLink{
+				Target:  strings.TrimSpace(match[2]),
+				Heading: strings.TrimPrefix(match[3], "#"),
+				Alias:   strings.TrimPrefix(match[4], "|"),
+			}
+			key := strings.ToLower(link.Target)
+			if link.Target == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			links = append(links, link)
+		}
+		return line
+	})
+	return links
+}
+
+// ReplaceWikiLinkTarget points links to oldTarget (matched case-insensitively)
+// at newTarget, keeping any heading and alias.
+func ReplaceWikiLinkTarget(body, oldTarget, newTarget string) string {
+	oldTarget = strings.TrimSpace(oldTarget)
+	return mapProseLines(body, func(_ int, line string) string {
+		return wikiLinkPattern.ReplaceAllStringFunc(line, func(raw string) string {
+			match := wikiLinkPattern.FindStringSubmatch(raw)
+			if match[1] == "!" || !strings.EqualFold(strings.TrimSpace(match[2]), oldTarget) {
+				return raw
+			}
+			return "[[" + newTarget + match[3] + match[4] + "]]"
+		})
+	})
+}
@@ -0,0 +1,70 @@
+package codec
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var hashtagPattern = regexp.MustCompile(`(?:^|[\s(])#([A-Za-z][A-Za-z0-9_-]*)`)
+
+// ExtractTags returns the #hashtags used in a note body, lowercased and
+// sorted. Fenced code blocks are ignored so shell comments and the like are
+// not picked up as tags.
+func ExtractTags(body string) []string {
+	var found []string
+	mapProseLines(body, func(_ int, line string) string {
+		for _, match := range hashtagPattern.FindAllStringSubmatch(line, -1) {
+			found = append(found, match[1])
+		}
+		return line
+	})
+	return NormalizeTags(found)
+}
+
+// mapProseLines applies fn to every line outside fenced code blocks and
+// returns the body with those lines replaced.
+func mapProseLines(body string, fn func(index int, line string) string) string {
+	lines := strings.Split(body, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		lines[i] = fn(i, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func ParseTagList(value string) []string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "[")
+	value = strings.TrimSuffix(value, "]")
+	return NormalizeTags(strings.Split(value, ","))
+}
+
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		tag = strings.Trim(tag, `"'`)
+		tag = strings.TrimLeft(tag, "#@")
+		tag = strings.Join(strings.Fields(tag), "-")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	sort.Strings(out)
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
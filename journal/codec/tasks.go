@@ -0,0 +1,54 @@
+package codec
+
+import (
+	"regexp"
+	"strings"
+)
+
+var taskPattern = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+\[)([ xX])(\]\s+)(.*)$`)
+
+// Task is a Markdown checklist item. Line is its zero-based line in the
+// note body.
+type Task struct {
+	Line int
+	Text string
+	Done bool
+}
+
+func ParseTasks(body string) []Task {
+	var tasks []Task
+	mapProseLines(body, func(index int, line string) string {
+		match := taskPattern.FindStringSubmatch(line)
+		if match == nil || strings.TrimSpace(match[4]) == "" {
+			return line
+		}
+		tasks = append(tasks, Task{
+			Line: index,
+			Text: strings.TrimSpace(match[4]),
+			Done: match[2] != " ",
+		})
+		return line
+	})
+	return tasks
+}
+
+// ToggleTask flips the checkbox on the given body line. It reports false
+// when that line is not a task.
+func ToggleTask(body string, line int) (string, bool) {
+	lines := strings.Split(body, "\n")
+	if line < 0 || line >= len(lines) {
+		return body, false
+	}
+	for _, task := range ParseTasks(body) {
+		if task.Line != line {
+			continue
+		}
+		mark := "x"
+		if task.Done {
+			mark = " "
+		}
+		lines[line] = taskPattern.ReplaceAllString(lines[line], "${1}"+mark+"${3}${4}")
+		return strings.Join(lines, "\n"), true
+	}
+	return body, false
+}
@@ -0,0 +1,50 @@
+package crypto
+
+import (
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+)
+
+// EncryptStream encrypts src to dst in age's binary format, for attachments
+// and other files that are not stored inside a note.
+func EncryptStream(dst io.Writer, src io.Reader, sshKeyPath string) error {
+	if sshKeyPath == "" {
+		return errMissingSSHKey
+	}
+	recipient, err := recipientFromKeyFile(sshKeyPath)
+	if err != nil {
+		return err
+	}
+	enc, err := age.Encrypt(dst, recipient)
+	if err != nil {
+		return fmt.Errorf("encrypt file: %w", err)
+	}
+	if _, err := io.Copy(enc, src); err != nil {
+		_ = enc.Close()
+		return fmt.Errorf("encrypt file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encrypt file: %w", err)
+	}
+	return nil
+}
+
+func DecryptStream(dst io.Writer, src io.Reader, sshKeyPath string) error {
+	if sshKeyPath == "" {
+		return errMissingSSHKey
+	}
+	identity, err := identityFromKeyFile(sshKeyPath)
+	if err != nil {
+		return err
+	}
+	dec, err := age.Decrypt(src, identity)
+	if err != nil {
+		return fmt.Errorf("decrypt file: %w", err)
+	}
+	if _, err := io.Copy(dst, dec); err != nil {
+		return fmt.Errorf("decrypt file: %w", err)
+	}
+	return nil
+}
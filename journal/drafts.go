@@ -0,0 +1,127 @@
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/never00rei/a7/journal/crypto"
+)
+
+const DraftsDir = ".drafts"
+
+const (
+	draftExt          = ".draft"
+	encryptedDraftTag = "-----BEGIN AGE ENCRYPTED FILE-----"
+)
+
+// Draft is unsaved editor text kept so it can be recovered after a crash or
+// a closed terminal. File is empty for notes that were never saved.
+type Draft struct {
+	ID            string    `json:"-"`
+	File          string    `json:"file,omitempty"`
+	OriginalTitle string    `json:"original_title,omitempty"`
+	Title         string    `json:"title"`
+	Body          string    `json:"body"`
+	Prompt        string    `json:"prompt,omitempty"`
+	Created       time.Time `json:"created"`
+	Saved         time.Time `json:"saved"`
+}
+
+// DraftID names the draft for a note being edited, or for a new note by its
+// creation time.
+func DraftID(filename string, created time.Time) string {
+	if filename != "" {
+		return NoteID(filename)
+	}
+	return "new-" + created.Format("20060102-150405")
+}
+
+// SaveDraft writes the draft under the journal's .drafts folder, encrypted
+// when encryption is on or the note being edited is encrypted.
+func (s *Service) SaveDraft(draft Draft) error {
+	if draft.ID == "" {
+		draft.ID = DraftID(draft.File, draft.Created)
+	}
+	draft.Saved = time.Now()
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return fmt.Errorf("save draft: %w", err)
+	}
+	content := string(data)
+	if s.Encrypt || (draft.File != "" && s.noteEncrypted(draft.File)) {
+		content, err = crypto.EncryptBody(content, s.SSHKeyPath)
+		if err != nil {
+			return fmt.Errorf("save draft: %w", err)
+		}
+	}
+
+	err = s.store.Replace(path.Join(DraftsDir, draft.ID+draftExt), func(w io.Writer) error {
+		_, err := io.WriteString(w, content)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("save draft: %w", err)
+	}
+	return nil
+}
+
+// ListDrafts returns the saved drafts, most recently saved first, and the
+// IDs of drafts that could not be read, such as ones encrypted with another
+// key, so one bad draft does not hide the others.
+func (s *Service) ListDrafts() ([]Draft, []string, error) {
+	files, err := s.store.ListFiles(DraftsDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	drafts := make([]Draft, 0, len(files))
+	var unreadable []string
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name, draftExt) {
+			continue
+		}
+		draft, err := s.loadDraft(file.Name)
+		if err != nil {
+			unreadable = append(unreadable, strings.TrimSuffix(file.Name, draftExt))
+			continue
+		}
+		drafts = append(drafts, draft)
+	}
+	sort.SliceStable(drafts, func(i, j int) bool {
+		return drafts[i].Saved.After(drafts[j].Saved)
+	})
+	return drafts, unreadable, nil
+}
+
+func (s *Service) DiscardDraft(id string) error {
+	return s.store.Remove(path.Join(DraftsDir, id+draftExt))
+}
+
+func (s *Service) loadDraft(name string) (Draft, error) {
+	in, err := s.store.OpenFile(path.Join(DraftsDir, name))
+	if err != nil {
+		return Draft{}, err
+	}
+	data, err := io.ReadAll(in)
+	in.Close()
+	if err != nil {
+		return Draft{}, fmt.Errorf("load draft: %w", err)
+	}
+	content := string(data)
+	if strings.HasPrefix(content, encryptedDraftTag) {
+		content, err = crypto.DecryptBody(content, s.SSHKeyPath)
+		if err != nil {
+			return Draft{}, fmt.Errorf("decrypt draft: %w", err)
+		}
+	}
+	var draft Draft
+	if err := json.Unmarshal([]byte(content), &draft); err != nil {
+		return Draft{}, fmt.Errorf("load draft %s: %w", name, err)
+	}
+	draft.ID = strings.TrimSuffix(name, draftExt)
+	return draft, nil
+}
@@ -0,0 +1,211 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"html"
+	"io"
+	"text/template"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+var xhtmlMarkdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(gmhtml.WithXHTML()),
+)
+
+type epubChapter struct {
+	ID    string
+	File  string
+	Title string
+	Meta  string
+	Body  string
+}
+
+type epubBook struct {
+	Title    string
+	UID      string
+	Modified string
+	Chapters []epubChapter
+}
+
+// EPUB writes the selected notes as an EPUB 3 book, one chapter per note in
+// creation order, with both a navigation document and an NCX table of
+// contents for older readers.
+func EPUB(svc *journal.Service, w io.Writer, opts Options) (Report, error) {
+	entries, report, err := collect(svc, opts)
+	if err != nil {
+		return report, err
+	}
+
+	book := epubBook{
+		Title:    opts.title(),
+		Modified: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	digest := sha1.New()
+	for i, entry := range entries {
+		chapter := epubChapter{
+			ID:    fmt.Sprintf("entry%04d", i+1),
+			Title: entry.Title(),
+			Meta:  entryMetaLine(entry),
+		}
+		chapter.File = chapter.ID + ".xhtml"
+		if entry.Locked {
+			chapter.Body = "<p><em>This entry is encrypted and was exported without its key.</em></p>"
+		} else {
+			var buf bytes.Buffer
+			if err := xhtmlMarkdown.Convert([]byte(entry.Content()), &buf); err != nil {
+				return report, fmt.Errorf("render %s: %w", entry.Info.Filename, err)
+			}
+			chapter.Body = buf.String()
+		}
+		digest.Write([]byte(entry.Info.Filename))
+		book.Chapters = append(book.Chapters, chapter)
+	}
+	book.UID = fmt.Sprintf("urn:a7:%x", digest.Sum(nil))
+
+	archive := zip.NewWriter(w)
+	// The mimetype entry must come first and be stored uncompressed.
+	mimetype, err := archive.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return report, err
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return report, err
+	}
+
+	files := []struct {
+		name string
+		tmpl string
+		data any
+	}{
+		{"META-INF/container.xml", "container", book},
+		{"OEBPS/content.opf", "opf", book},
+		{"OEBPS/nav.xhtml", "nav", book},
+		{"OEBPS/toc.ncx", "ncx", book},
+	}
+	for _, chapter := range book.Chapters {
+		files = append(files, struct {
+			name string
+			tmpl string
+			data any
+		}{"OEBPS/" + chapter.File, "chapter", chapter})
+	}
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return report, err
+		}
+		if err := epubTemplates.ExecuteTemplate(writer, file.tmpl, file.data); err != nil {
+			return report, fmt.Errorf("render %s: %w", file.name, err)
+		}
+	}
+	style, err := archive.Create("OEBPS/style.css")
+	if err != nil {
+		return report, err
+	}
+	if _, err := io.WriteString(style, epubStylesheet); err != nil {
+		return report, err
+	}
+	if err := archive.Close(); err != nil {
+		return report, err
+	}
+
+	report.Written = len(entries)
+	return report, nil
+}
+
+var epubTemplates = template.Must(template.New("epub").Funcs(template.FuncMap{
+	"esc":  html.EscapeString,
+	"next": func(i int) int { return i + 1 },
+}).Parse(`
+{{define "container"}}<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+{{end}}
+{{define "opf"}}<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="uid">{{esc .UID}}</dc:identifier>
+    <dc:title>{{esc .Title}}</dc:title>
+    <dc:language>en</dc:language>
+    <meta property="dcterms:modified">{{.Modified}}</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+    <item id="style" href="style.css" media-type="text/css"/>
+{{- range .Chapters}}
+    <item id="{{.ID}}" href="{{.File}}" media-type="application/xhtml+xml"/>
+{{- end}}
+  </manifest>
+  <spine toc="ncx">
+{{- range .Chapters}}
+    <itemref idref="{{.ID}}"/>
+{{- end}}
+  </spine>
+</package>
+{{end}}
+{{define "nav"}}<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><title>{{esc .Title}}</title></head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <h1>{{esc .Title}}</h1>
+    <ol>
+{{- range .Chapters}}
+      <li><a href="{{.File}}">{{esc .Title}}</a></li>
+{{- end}}
+    </ol>
+  </nav>
+</body>
+</html>
+{{end}}
+{{define "ncx"}}<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head><meta name="dtb:uid" content="{{esc .UID}}"/></head>
+  <docTitle><text>{{esc .Title}}</text></docTitle>
+  <navMap>
+{{- range $i, $c := .Chapters}}
+    <navPoint id="nav-{{$c.ID}}" playOrder="{{next $i}}">
+      <navLabel><text>{{esc $c.Title}}</text></navLabel>
+      <content src="{{$c.File}}"/>
+    </navPoint>
+{{- end}}
+  </navMap>
+</ncx>
+{{end}}
+{{define "chapter"}}<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+  <title>{{esc .Title}}</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+  <h1>{{esc .Title}}</h1>
+{{- if .Meta}}
+  <p class="meta">{{esc .Meta}}</p>
+{{- end}}
+{{.Body}}
+</body>
+</html>
+{{end}}
+`))
+
+const epubStylesheet = `body { font-family: serif; line-height: 1.5; }
+h1 { font-size: 1.4em; margin-bottom: 0.2em; }
+.meta { color: #666; font-size: 0.85em; margin-top: 0; }
+pre { white-space: pre-wrap; font-size: 0.85em; }
+`
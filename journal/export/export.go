@@ -0,0 +1,155 @@
+package export
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/codec"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Entry is a note prepared for export. Locked entries are encrypted notes
+// that could not be decrypted, so only their metadata is available.
+type Entry struct {
+	ID     string
+	Info   journal.NoteInfo
+	Note   *journal.Note
+	Locked bool
+}
+
+// Options select which notes are exported. From and To bound the note date
+// (both inclusive, by day), Tags matches notes with any of the given tags.
+// ExcludeEncrypted leaves out every encrypted note; SkipEncrypted only the
+// ones that cannot be decrypted with the service key.
+type Options struct {
+	From             time.Time
+	To               time.Time
+	Tags             []string
+	ExcludeEncrypted bool
+	SkipEncrypted    bool
+	Title            string
+}
+
+type Report struct {
+	Written int
+	Locked  int
+	Skipped int
+}
+
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+func collect(svc *journal.Service, opts Options) ([]Entry, Report, error) {
+	var report Report
+	notes, err := svc.ListNotes()
+	if err != nil {
+		return nil, report, err
+	}
+
+	entries := make([]Entry, 0, len(notes))
+	for _, info := range notes {
+		if !opts.matches(info) {
+			continue
+		}
+		if info.Encrypted && opts.ExcludeEncrypted {
+			report.Skipped++
+			continue
+		}
+		note, err := svc.LoadNote(info.Filename)
+		if note == nil {
+			return nil, report, err
+		}
+		entry := Entry{ID: NoteID(info.Filename), Info: info, Note: note, Locked: err != nil}
+		if entry.Locked {
+			if opts.SkipEncrypted {
+				report.Skipped++
+				continue
+			}
+			report.Locked++
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, _ := entries[i].Info.Date()
+		b, _ := entries[j].Info.Date()
+		return a.Before(b)
+	})
+	return entries, report, nil
+}
+
+func (o Options) matches(info journal.NoteInfo) bool {
+	if !o.From.IsZero() || !o.To.IsZero() {
+		date, ok := info.Date()
+		if !ok {
+			return false
+		}
+		day := dayOf(date)
+		if !o.From.IsZero() && day.Before(dayOf(o.From)) {
+			return false
+		}
+		if !o.To.IsZero() && day.After(dayOf(o.To)) {
+			return false
+		}
+	}
+	if len(o.Tags) == 0 {
+		return true
+	}
+	wanted := codec.NormalizeTags(o.Tags)
+	for _, tag := range info.Tags {
+		for _, want := range wanted {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (o Options) title() string {
+	if strings.TrimSpace(o.Title) != "" {
+		return o.Title
+	}
+	return "Journal"
+}
+
+func reversed(entries []Entry) []Entry {
+	out := make([]Entry, len(entries))
+	for i, entry := range entries {
+		out[len(entries)-1-i] = entry
+	}
+	return out
+}
+
+func (e Entry) Content() string {
+	if e.Locked || e.Note == nil {
+		return ""
+	}
+	return e.Note.Content
+}
+
+func NoteID(filename string) string {
+	return journal.NoteID(filename)
+}
+
+func (e Entry) Title() string {
+	if strings.TrimSpace(e.Info.Title) != "" {
+		return e.Info.Title
+	}
+	return e.ID
+}
+
+func renderHTML(body string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(body), &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,182 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+)
+
+func newTestJournal(t *testing.T) *journal.Service {
+	t.Helper()
+	svc := journal.NewService(t.TempDir())
+	notes := []struct {
+		title   string
+		body    string
+		created time.Time
+	}{
+		{"First day", "Hello **world** #travel", time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{"Second day", "More notes #travel #food", time.Date(2024, 2, 3, 9, 0, 0, 0, time.UTC)},
+		{"Third day", "Plain entry", time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, note := range notes {
+		if _, err := svc.SaveNote(note.title, note.body, note.created); err != nil {
+			t.Fatalf("SaveNote: %v", err)
+		}
+	}
+	return svc
+}
+
+func TestHTMLWritesSite(t *testing.T) {
+	svc := newTestJournal(t)
+	dir := t.TempDir()
+
+	report, err := HTML(svc, dir, Options{})
+	if err != nil {
+		t.Fatalf("HTML: %v", err)
+	}
+	if report.Written != 3 {
+		t.Fatalf("Written = %d, want 3", report.Written)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatalf("index missing: %v", err)
+	}
+	if !strings.Contains(string(index), "January 2024") || !strings.Contains(string(index), "First day") {
+		t.Fatalf("index missing month or entry:\n%s", index)
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "notes", "2024-01-02_09-00_First_day.html"))
+	if err != nil {
+		t.Fatalf("note page missing: %v", err)
+	}
+	if !strings.Contains(string(page), "<strong>world</strong>") {
+		t.Fatalf("note body not rendered as HTML:\n%s", page)
+	}
+
+	tag, err := os.ReadFile(filepath.Join(dir, "tags", "travel.html"))
+	if err != nil {
+		t.Fatalf("tag page missing: %v", err)
+	}
+	if strings.Count(string(tag), "<li>") != 2 {
+		t.Fatalf("travel tag page should list two entries:\n%s", tag)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "style.css")); err != nil {
+		t.Fatalf("stylesheet missing: %v", err)
+	}
+}
+
+func TestTagFilesDoNotCollide(t *testing.T) {
+	seen := make(map[string]string)
+	for _, tag := range []string{"a-b", "a_b", "a b", "a/b", "a_2fb", "café"} {
+		name := tagFile(tag)
+		if other, ok := seen[name]; ok {
+			t.Fatalf("tags %q and %q share %s", other, tag, name)
+		}
+		if filepath.Base(name) != name {
+			t.Fatalf("tagFile(%q) = %q, want a plain file name", tag, name)
+		}
+		seen[name] = tag
+	}
+	if got := tagFile("travel-2024"); got != "travel-2024.html" {
+		t.Fatalf("tagFile = %q, want travel-2024.html", got)
+	}
+}
+
+func TestJSONFiltersByTagAndDate(t *testing.T) {
+	svc := newTestJournal(t)
+	var buf bytes.Buffer
+
+	opts := Options{Tags: []string{"#Travel"}, From: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}
+	report, err := JSON(svc, &buf, opts)
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	if report.Written != 1 {
+		t.Fatalf("Written = %d, want 1", report.Written)
+	}
+
+	var archive jsonArchive
+	if err := json.Unmarshal(buf.Bytes(), &archive); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(archive.Entries) != 1 || archive.Entries[0].Title != "Second day" {
+		t.Fatalf("entries = %+v, want only Second day", archive.Entries)
+	}
+	if !strings.Contains(archive.Entries[0].Content, "More notes") {
+		t.Fatalf("content = %q, want note body", archive.Entries[0].Content)
+	}
+}
+
+func TestEPUBWritesBook(t *testing.T) {
+	svc := newTestJournal(t)
+	var buf bytes.Buffer
+
+	report, err := EPUB(svc, &buf, Options{To: time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("EPUB: %v", err)
+	}
+	if report.Written != 2 {
+		t.Fatalf("Written = %d, want 2", report.Written)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	if first := archive.File[0]; first.Name != "mimetype" || first.Method != zip.Store {
+		t.Fatalf("first file = %s (method %d), want stored mimetype", first.Name, first.Method)
+	}
+	files := map[string]bool{}
+	for _, file := range archive.File {
+		files[file.Name] = true
+	}
+	for _, name := range []string{"META-INF/container.xml", "OEBPS/content.opf", "OEBPS/nav.xhtml", "OEBPS/entry0001.xhtml", "OEBPS/entry0002.xhtml"} {
+		if !files[name] {
+			t.Fatalf("missing %s in %v", name, files)
+		}
+	}
+	if files["OEBPS/entry0003.xhtml"] {
+		t.Fatalf("entry after --to date should be excluded")
+	}
+	for _, file := range archive.File {
+		if file.Name != "OEBPS/toc.ncx" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("open ncx: %v", err)
+		}
+		ncx, _ := io.ReadAll(rc)
+		rc.Close()
+		if strings.Contains(string(ncx), `playOrder="0"`) || !strings.Contains(string(ncx), `playOrder="2"`) {
+			t.Fatalf("playOrder should count from 1:\n%s", ncx)
+		}
+	}
+}
+
+func TestMarkdownBundleOrdersOldestFirst(t *testing.T) {
+	svc := newTestJournal(t)
+	var buf bytes.Buffer
+
+	if _, err := Markdown(svc, &buf, Options{Title: "Archive"}); err != nil {
+		t.Fatalf("Markdown: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "# Archive\n") {
+		t.Fatalf("missing title heading:\n%s", out)
+	}
+	first := strings.Index(out, "## First day")
+	third := strings.Index(out, "## Third day")
+	if first < 0 || third < 0 || first > third {
+		t.Fatalf("entries not in date order:\n%s", out)
+	}
+}
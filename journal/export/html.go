@@ -0,0 +1,281 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+)
+
+type htmlMonth struct {
+	Label   string
+	Entries []Entry
+}
+
+type htmlTag struct {
+	Name    string
+	Entries []Entry
+}
+
+type htmlLink struct {
+	Root  string
+	Entry Entry
+}
+
+type htmlPage struct {
+	Title   string
+	Root    string
+	Months  []htmlMonth
+	Tags    []htmlTag
+	Tag     htmlTag
+	Entry   Entry
+	Body    template.HTML
+	Created string
+	Updated string
+}
+
+// HTML renders the journal as a static site in dir: an index ordered by
+// date, newest first, one page per tag and one page per note.
+func HTML(svc *journal.Service, dir string, opts Options) (Report, error) {
+	entries, report, err := collect(svc, opts)
+	if err != nil {
+		return report, err
+	}
+
+	for _, sub := range []string{"notes", "tags"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return report, fmt.Errorf("create export dir: %w", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "style.css"), []byte(stylesheet), 0644); err != nil {
+		return report, fmt.Errorf("write stylesheet: %w", err)
+	}
+
+	newestFirst := reversed(entries)
+	tags := groupByTag(newestFirst)
+	index := htmlPage{Title: opts.title(), Root: ".", Months: groupByMonth(newestFirst), Tags: tags}
+	if err := writePage(filepath.Join(dir, "index.html"), "index", index); err != nil {
+		return report, err
+	}
+	if err := writePage(filepath.Join(dir, "tags", "index.html"), "tags", htmlPage{Title: "Tags", Root: "..", Tags: tags}); err != nil {
+		return report, err
+	}
+	for _, tag := range tags {
+		page := htmlPage{Title: "#" + tag.Name, Root: "..", Tag: tag}
+		if err := writePage(filepath.Join(dir, "tags", tagFile(tag.Name)), "tag", page); err != nil {
+			return report, err
+		}
+	}
+
+	for _, entry := range entries {
+		page := htmlPage{Title: entry.Title(), Root: "..", Entry: entry}
+		if date, ok := entry.Info.Date(); ok {
+			page.Created = date.Local().Format(time.RFC1123)
+		}
+		if updated := entry.Info.LastUpdated(); !updated.IsZero() {
+			page.Updated = updated.Local().Format(time.RFC1123)
+		}
+		if !entry.Locked {
+			body, err := renderHTML(entry.Note.Content)
+			if err != nil {
+				return report, fmt.Errorf("render %s: %w", entry.Info.Filename, err)
+			}
+			page.Body = template.HTML(body)
+		}
+		if err := writePage(filepath.Join(dir, "notes", entry.ID+".html"), "note", page); err != nil {
+			return report, err
+		}
+		report.Written++
+	}
+
+	return report, nil
+}
+
+func writePage(path, name string, page htmlPage) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+	if err := pages.ExecuteTemplate(file, name, page); err != nil {
+		return fmt.Errorf("render %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func groupByMonth(entries []Entry) []htmlMonth {
+	var months []htmlMonth
+	for _, entry := range entries {
+		label := "Undated"
+		if date, ok := entry.Info.Date(); ok {
+			label = date.Local().Format("January 2006")
+		}
+		if len(months) == 0 || months[len(months)-1].Label != label {
+			months = append(months, htmlMonth{Label: label})
+		}
+		months[len(months)-1].Entries = append(months[len(months)-1].Entries, entry)
+	}
+	return months
+}
+
+func groupByTag(entries []Entry) []htmlTag {
+	byTag := map[string][]Entry{}
+	for _, entry := range entries {
+		for _, tag := range entry.Info.Tags {
+			byTag[tag] = append(byTag[tag], entry)
+		}
+	}
+	tags := make([]htmlTag, 0, len(byTag))
+	for name, tagged := range byTag {
+		tags = append(tags, htmlTag{Name: name, Entries: tagged})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return tags
+}
+
+// tagFile names a tag's page. Lower case letters, digits and hyphens are
+// kept and every other byte, underscores included, is written as _ and two
+// hex digits, so distinct tags never share a page.
+func tagFile(tag string) string {
+	var name strings.Builder
+	for i := 0; i < len(tag); i++ {
+		c := tag[i]
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' {
+			name.WriteByte(c)
+		} else {
+			fmt.Fprintf(&name, "_%02x", c)
+		}
+	}
+	return name.String() + ".html"
+}
+
+var pages = template.Must(template.New("pages").Funcs(template.FuncMap{
+	"date": func(entry Entry) string {
+		if date, ok := entry.Info.Date(); ok {
+			return date.Local().Format("Mon 2 Jan 2006")
+		}
+		return ""
+	},
+	"link": func(root string, entry Entry) htmlLink {
+		return htmlLink{Root: root, Entry: entry}
+	},
+	"tagfile": tagFile,
+}).Parse(`
+{{define "head"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Root}}/style.css">
+</head>
+<body>
+<nav><a href="{{.Root}}/index.html">Journal</a> · <a href="{{.Root}}/tags/index.html">Tags</a></nav>
+<main>
+{{end}}
+{{define "foot"}}</main>
+</body>
+</html>
+{{end}}
+{{define "entry-link"}}<li><a href="{{.Root}}/notes/{{.Entry.ID}}.html">{{.Entry.Title}}</a> <span class="date">{{date .Entry}}</span>{{if .Entry.Locked}} <span class="locked">encrypted</span>{{end}}</li>{{end}}
+{{define "index"}}{{template "head" .}}<h1>{{.Title}}</h1>
+{{$root := .Root}}{{range .Months}}<section>
+<h2>{{.Label}}</h2>
+<ul>{{range .Entries}}
+{{template "entry-link" (link $root .)}}{{end}}
+</ul>
+</section>
+{{else}}<p>No entries yet.</p>
+{{end}}{{template "foot" .}}{{end}}
+{{define "tags"}}{{template "head" .}}<h1>Tags</h1>
+<ul class="tags">{{range .Tags}}
+<li><a href="{{tagfile .Name}}">#{{.Name}}</a> ({{len .Entries}})</li>{{else}}
+<li>No tags yet.</li>{{end}}
+</ul>
+{{template "foot" .}}{{end}}
+{{define "tag"}}{{template "head" .}}<h1>{{.Title}}</h1>
+{{$root := .Root}}<ul>{{range .Tag.Entries}}
+{{template "entry-link" (link $root .)}}{{end}}
+</ul>
+{{template "foot" .}}{{end}}
+{{define "note"}}{{template "head" .}}<article>
+<h1>{{.Title}}</h1>
+<dl class="meta">
+{{if .Created}}<dt>Created</dt><dd>{{.Created}}</dd>{{end}}
+{{if .Updated}}<dt>Updated</dt><dd>{{.Updated}}</dd>{{end}}
+{{if ge .Entry.Info.WordCount 0}}<dt>Words</dt><dd>{{.Entry.Info.WordCount}}</dd>{{end}}
+<dt>Encrypted</dt><dd>{{if .Entry.Info.Encrypted}}Yes{{else}}No{{end}}</dd>
+{{if .Entry.Info.Prompt}}<dt>Prompt</dt><dd>{{.Entry.Info.Prompt}}</dd>{{end}}
+{{if .Entry.Info.Tags}}<dt>Tags</dt><dd>{{range .Entry.Info.Tags}}<a href="../tags/{{tagfile .}}">#{{.}}</a> {{end}}</dd>{{end}}
+</dl>
+{{if .Entry.Locked}}<p class="locked">This entry is encrypted and was exported without its key.</p>
+{{else}}<div class="body">
+{{.Body}}
+</div>
+{{end}}</article>
+{{template "foot" .}}{{end}}
+`))
+
+const stylesheet = `body {
+  margin: 0;
+  font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif;
+  line-height: 1.6;
+  color: #1f2328;
+  background: #fafafa;
+}
+nav {
+  padding: 0.75rem 1.5rem;
+  border-bottom: 1px solid #d0d7de;
+  background: #fff;
+}
+main {
+  max-width: 46rem;
+  margin: 0 auto;
+  padding: 1.5rem;
+}
+a {
+  color: #0969da;
+  text-decoration: none;
+}
+a:hover {
+  text-decoration: underline;
+}
+ul {
+  padding-left: 1.2rem;
+}
+.date {
+  color: #656d76;
+  font-size: 0.9em;
+}
+.locked {
+  color: #9a6700;
+}
+.meta {
+  display: grid;
+  grid-template-columns: max-content 1fr;
+  gap: 0.2rem 1rem;
+  color: #656d76;
+  font-size: 0.9em;
+}
+.meta dd {
+  margin: 0;
+}
+.body pre {
+  overflow-x: auto;
+  padding: 0.75rem;
+  background: #f0f2f4;
+}
+@media (prefers-color-scheme: dark) {
+  body { color: #e6edf3; background: #0d1117; }
+  nav { background: #161b22; border-color: #30363d; }
+  a { color: #4493f8; }
+  .body pre { background: #161b22; }
+}
+`
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+)
+
+type jsonEntry struct {
+	Filename  string    `json:"filename"`
+	Title     string    `json:"title"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+	ModTime   time.Time `json:"mod_time"`
+	Encrypted bool      `json:"encrypted"`
+	Locked    bool      `json:"locked"`
+	WordCount int       `json:"word_count"`
+	Prompt    string    `json:"prompt,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Content   string    `json:"content"`
+}
+
+type jsonArchive struct {
+	Title      string      `json:"title"`
+	ExportedAt time.Time   `json:"exported_at"`
+	Entries    []jsonEntry `json:"entries"`
+}
+
+// JSON writes every selected note, metadata and body, as a single document.
+func JSON(svc *journal.Service, w io.Writer, opts Options) (Report, error) {
+	entries, report, err := collect(svc, opts)
+	if err != nil {
+		return report, err
+	}
+
+	archive := jsonArchive{
+		Title:      opts.title(),
+		ExportedAt: time.Now(),
+		Entries:    make([]jsonEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		created, _ := entry.Info.Date()
+		archive.Entries = append(archive.Entries, jsonEntry{
+			Filename:  entry.Info.Filename,
+			Title:     entry.Title(),
+			Created:   created,
+			Updated:   entry.Info.LastUpdated(),
+			ModTime:   entry.Info.ModTime,
+			Encrypted: entry.Info.Encrypted,
+			Locked:    entry.Locked,
+			WordCount: entry.Info.WordCount,
+			Prompt:    entry.Info.Prompt,
+			Tags:      entry.Info.Tags,
+			Content:   entry.Content(),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(archive); err != nil {
+		return report, err
+	}
+	report.Written = len(entries)
+	return report, nil
+}
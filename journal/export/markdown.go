@@ -0,0 +1,59 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+)
+
+// Markdown concatenates the selected notes, oldest first, into one document
+// with a heading and a metadata line per entry.
+func Markdown(svc *journal.Service, w io.Writer, opts Options) (Report, error) {
+	entries, report, err := collect(svc, opts)
+	if err != nil {
+		return report, err
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "# %s\n\n", opts.title())
+	for _, entry := range entries {
+		fmt.Fprintf(out, "## %s\n\n", entry.Title())
+		if meta := entryMetaLine(entry); meta != "" {
+			fmt.Fprintf(out, "*%s*\n\n", meta)
+		}
+		if entry.Locked {
+			out.WriteString("> This entry is encrypted and was exported without its key.\n\n")
+		} else {
+			out.WriteString(strings.TrimRight(entry.Content(), "\n"))
+			out.WriteString("\n\n")
+		}
+		out.WriteString("---\n\n")
+		report.Written++
+	}
+	if err := out.Flush(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func entryMetaLine(entry Entry) string {
+	var parts []string
+	if date, ok := entry.Info.Date(); ok {
+		parts = append(parts, date.Local().Format(time.RFC1123))
+	}
+	if entry.Info.WordCount >= 0 {
+		parts = append(parts, fmt.Sprintf("%d words", entry.Info.WordCount))
+	}
+	if len(entry.Info.Tags) > 0 {
+		tags := make([]string, 0, len(entry.Info.Tags))
+		for _, tag := range entry.Info.Tags {
+			tags = append(tags, "#"+tag)
+		}
+		parts = append(parts, strings.Join(tags, " "))
+	}
+	return strings.Join(parts, " · ")
+}
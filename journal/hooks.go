@@ -0,0 +1,70 @@
+package journal
+
+import (
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/never00rei/a7/journal/codec"
+	"github.com/never00rei/a7/journal/hooks"
+	"github.com/never00rei/a7/journal/store"
+)
+
+const deletedBackupPrefix = "deleted-"
+
+// OpenNote loads a note the user is about to read or edit and runs the
+// on-open hooks for it.
+func (s *Service) OpenNote(filename string) (*Note, error) {
+	note, err := s.LoadNote(filename)
+	if err != nil || s.hooks == nil {
+		return note, err
+	}
+	matter := codec.FrontMatter{Title: note.Title, Created: note.Created, Tags: note.Tags, Encrypted: note.Encrypted}
+	s.hooks.Notify(hooks.OnOpen, s.hookPayload(filename, matter, note.Content))
+	return note, nil
+}
+
+// DeleteNote moves a note and its attachments into a .backup/deleted-<time>
+// folder, so a mistaken delete can be undone by moving them back, runs the
+// post-delete hooks and returns the backup folder.
+func (s *Service) DeleteNote(filename string) (string, error) {
+	info, err := s.StatNote(filename)
+	if err != nil {
+		return "", err
+	}
+	set := deletedBackupPrefix + time.Now().Format("2006-01-02_15-04-05")
+	err = s.withNoteLock(filename, "delete", func() error {
+		if err := s.store.MoveToBackup(set, filename); err != nil {
+			return err
+		}
+		return s.store.MoveToBackup(set, path.Join(AttachmentsDir, NoteID(filename)))
+	})
+	if err != nil {
+		return "", err
+	}
+	if s.hooks != nil {
+		matter := codec.FrontMatter{Title: info.Title, Created: info.Created, Tags: info.Tags, Encrypted: info.Encrypted}
+		s.hooks.Notify(hooks.PostDelete, s.hookPayload(filename, matter, ""))
+	}
+	return filepath.Join(s.Root, store.BackupDir, set), nil
+}
+
+func (s *Service) hookPayload(filename string, matter codec.FrontMatter, body string) hooks.Payload {
+	created := matter.Created
+	if created.IsZero() {
+		created, _ = codec.ParseFilenameTimestampIn(filename, s.location)
+	}
+	return hooks.Payload{
+		Journal: s.Root,
+		Note: hooks.Note{
+			ID:        NoteID(filename),
+			Filename:  filename,
+			Path:      filepath.Join(s.Root, filename),
+			Title:     matter.Title,
+			Created:   created,
+			Tags:      codec.NormalizeTags(append(append([]string(nil), matter.Tags...), codec.ExtractTags(body)...)),
+			Encrypted: matter.Encrypted,
+			Body:      body,
+		},
+	}
+}
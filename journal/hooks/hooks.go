@@ -0,0 +1,228 @@
+// Package hooks runs the user's scripts when journal events happen. A hook
+// is an executable in the hooks folder named after its event, alone or
+// followed by a dot and any suffix, such as pre-save or post-save.sync.sh.
+// Hooks for one event run in name order.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Event string
+
+const (
+	PreSave    Event = "pre-save"
+	PostSave   Event = "post-save"
+	PostDelete Event = "post-delete"
+	OnOpen     Event = "on-open"
+)
+
+const DefaultTimeout = 10 * time.Second
+
+// ErrRejected is wrapped by the error for a pre-save hook that exited with
+// a non-zero status to stop the save.
+var ErrRejected = errors.New("rejected by hook")
+
+// Note describes the note an event is about. Body is the plain text body,
+// left out for post-delete.
+type Note struct {
+	ID        string    `json:"id"`
+	Filename  string    `json:"filename"`
+	Path      string    `json:"path"`
+	Title     string    `json:"title"`
+	Created   time.Time `json:"created"`
+	Tags      []string  `json:"tags,omitempty"`
+	Encrypted bool      `json:"encrypted"`
+	Body      string    `json:"body,omitempty"`
+}
+
+// Payload is written to each hook's stdin as JSON.
+type Payload struct {
+	Event   Event  `json:"event"`
+	Journal string `json:"journal"`
+	Note    Note   `json:"note"`
+}
+
+// Output is what a pre-save hook may print on stdout to replace the body.
+// Anything on stdout that is not such an object is ignored.
+type Output struct {
+	Body *string `json:"body"`
+}
+
+type Error struct {
+	Hook  string
+	Event Event
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s hook %s: %v", e.Event, e.Hook, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Call is a run of the hooks for an event that has already happened.
+type Call struct {
+	Event   Event
+	Payload Payload
+}
+
+// Runner finds and runs hooks in Dir. Report receives failures of hooks
+// that run after the fact, which never undo the save, delete or open. When
+// Defer is set, Notify hands it those runs instead, for a caller that runs
+// them later with Run, away from anything waiting on the change.
+type Runner struct {
+	Dir     string
+	Timeout time.Duration
+	Report  func(error)
+	Defer   func(Call)
+}
+
+func NewRunner(dir string) *Runner {
+	return &Runner{Dir: dir, Timeout: DefaultTimeout}
+}
+
+// PreSave runs the pre-save hooks in turn, each seeing the body left by the
+// one before, and returns the final body. The first hook that fails stops
+// the save.
+func (r *Runner) PreSave(payload Payload) (string, error) {
+	payload.Event = PreSave
+	for _, hook := range r.find(PreSave) {
+		stdout, err := r.run(hook, payload)
+		if err != nil {
+			return "", err
+		}
+		var out Output
+		trimmed := bytes.TrimSpace(stdout)
+		if len(trimmed) > 0 && trimmed[0] == '{' && json.Unmarshal(trimmed, &out) == nil && out.Body != nil {
+			payload.Note.Body = *out.Body
+		}
+	}
+	return payload.Note.Body, nil
+}
+
+// Notify runs the hooks for an event that has already happened, passing
+// any failures to Report.
+func (r *Runner) Notify(event Event, payload Payload) {
+	call := Call{Event: event, Payload: payload}
+	if r.Defer != nil {
+		r.Defer(call)
+		return
+	}
+	for _, err := range r.Run(call) {
+		if r.Report != nil {
+			r.Report(err)
+		}
+	}
+}
+
+// Run runs the hooks for a call and returns their failures.
+func (r *Runner) Run(call Call) []error {
+	call.Payload.Event = call.Event
+	var failures []error
+	for _, hook := range r.find(call.Event) {
+		if _, err := r.run(hook, call.Payload); err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return failures
+}
+
+func (r *Runner) find(event Event) []string {
+	if r == nil || r.Dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(r.Dir)
+	if err != nil {
+		return nil
+	}
+	var hooks []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if name != string(event) && !strings.HasPrefix(name, string(event)+".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || !executable(info.Mode()) {
+			continue
+		}
+		hooks = append(hooks, filepath.Join(r.Dir, name))
+	}
+	sort.Strings(hooks)
+	return hooks
+}
+
+func executable(mode os.FileMode) bool {
+	return runtime.GOOS == "windows" || mode&0111 != 0
+}
+
+func (r *Runner) run(hook string, payload Payload) ([]byte, error) {
+	hookErr := func(err error) error {
+		return &Error{Hook: filepath.Base(hook), Event: payload.Event, Err: err}
+	}
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return nil, hookErr(err)
+	}
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, hook)
+	cmd.Dir = payload.Journal
+	cmd.Env = append(os.Environ(), environ(payload)...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.WaitDelay = time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	switch {
+	case ctx.Err() != nil:
+		return nil, hookErr(fmt.Errorf("timed out after %s", timeout))
+	case err != nil:
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && payload.Event == PreSave {
+			err = ErrRejected
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, hookErr(err)
+	}
+	return stdout.Bytes(), nil
+}
+
+func environ(payload Payload) []string {
+	note := payload.Note
+	env := []string{
+		"A7_EVENT=" + string(payload.Event),
+		"A7_JOURNAL=" + payload.Journal,
+		"A7_NOTE_ID=" + note.ID,
+		"A7_NOTE_FILENAME=" + note.Filename,
+		"A7_NOTE_PATH=" + note.Path,
+		"A7_NOTE_TITLE=" + note.Title,
+		"A7_NOTE_TAGS=" + strings.Join(note.Tags, ","),
+		fmt.Sprintf("A7_NOTE_ENCRYPTED=%t", note.Encrypted),
+	}
+	if !note.Created.IsZero() {
+		env = append(env, "A7_NOTE_CREATED="+note.Created.Format(time.RFC3339))
+	}
+	return env
+}
@@ -0,0 +1,94 @@
+package hooks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeHook(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("write hook: %v", err)
+	}
+}
+
+func TestPreSaveChainsTransformsAndVetoes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	journal := t.TempDir()
+	writeHook(t, dir, "pre-save.1-redact", `sed -n 's/.*"body":"\([^"]*\)".*/\1/p' | sed 's/secret/[redacted]/' | awk '{printf "{\"body\":\"%s\"}", $0}'`+"\n")
+	writeHook(t, dir, "pre-save.2-lint", `echo "looks fine"; test "$A7_NOTE_TITLE" = "Diary"`+"\n")
+	writeHook(t, dir, "pre-save.disabled", "exit 1\n")
+	os.Chmod(filepath.Join(dir, "pre-save.disabled"), 0644)
+	runner := NewRunner(dir)
+
+	payload := Payload{Journal: journal, Note: Note{Title: "Diary", Body: "my secret plan"}}
+	body, err := runner.PreSave(payload)
+	if err != nil {
+		t.Fatalf("PreSave: %v", err)
+	}
+	if body != "my [redacted] plan" {
+		t.Fatalf("body = %q", body)
+	}
+
+	payload.Note.Title = "Other"
+	_, err = runner.PreSave(payload)
+	var hookErr *Error
+	if !errors.Is(err, ErrRejected) || !errors.As(err, &hookErr) || hookErr.Hook != "pre-save.2-lint" {
+		t.Fatalf("expected lint hook to reject, got %v", err)
+	}
+}
+
+func TestNotifyReportsFailuresAndTimeouts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	journal := t.TempDir()
+	writeHook(t, dir, "post-save", `cat > "$A7_JOURNAL/payload.json"; echo "$A7_EVENT $A7_NOTE_ID $A7_NOTE_TAGS" > "$A7_JOURNAL/env.txt"`+"\n")
+	writeHook(t, dir, "post-save.slow", "sleep 5\n")
+	writeHook(t, dir, "post-delete", "echo gone >&2; exit 3\n")
+	var failures []error
+	runner := NewRunner(dir)
+	runner.Timeout = 200 * time.Millisecond
+	runner.Report = func(err error) { failures = append(failures, err) }
+
+	start := time.Now()
+	runner.Notify(PostSave, Payload{Journal: journal, Note: Note{ID: "day", Tags: []string{"a", "b"}, Body: "text"}})
+	if time.Since(start) > 3*time.Second {
+		t.Fatalf("slow hook was not stopped")
+	}
+	env, err := os.ReadFile(filepath.Join(journal, "env.txt"))
+	if err != nil || strings.TrimSpace(string(env)) != "post-save day a,b" {
+		t.Fatalf("env = %q, %v", env, err)
+	}
+	payload, err := os.ReadFile(filepath.Join(journal, "payload.json"))
+	if err != nil || !strings.Contains(string(payload), `"event":"post-save"`) || !strings.Contains(string(payload), `"body":"text"`) {
+		t.Fatalf("payload = %s, %v", payload, err)
+	}
+	if len(failures) != 1 || !strings.Contains(failures[0].Error(), "timed out") {
+		t.Fatalf("failures = %v", failures)
+	}
+
+	runner.Notify(PostDelete, Payload{Journal: journal})
+	if len(failures) != 2 || !strings.Contains(failures[1].Error(), "gone") || errors.Is(failures[1], ErrRejected) {
+		t.Fatalf("failures = %v", failures)
+	}
+
+	var deferred []Call
+	runner.Defer = func(call Call) { deferred = append(deferred, call) }
+	runner.Notify(PostDelete, Payload{Journal: journal})
+	if len(failures) != 2 || len(deferred) != 1 {
+		t.Fatalf("deferred run reported or not deferred: failures %v, deferred %+v", failures, deferred)
+	}
+	if errs := runner.Run(deferred[0]); len(errs) != 1 || !strings.Contains(errs[0].Error(), "post-delete") {
+		t.Fatalf("deferred run failures = %v", errs)
+	}
+}
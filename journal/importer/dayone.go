@@ -0,0 +1,122 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+type dayOneExport struct {
+	Entries []dayOneEntry `json:"entries"`
+}
+
+type dayOneEntry struct {
+	UUID         string        `json:"uuid"`
+	CreationDate string        `json:"creationDate"`
+	TimeZone     string        `json:"timeZone"`
+	Text         string        `json:"text"`
+	Tags         []string      `json:"tags"`
+	Photos       []dayOneMedia `json:"photos"`
+	Videos       []dayOneMedia `json:"videos"`
+	Audios       []dayOneMedia `json:"audios"`
+	PDFs         []dayOneMedia `json:"pdfAttachments"`
+}
+
+type dayOneMedia struct {
+	Identifier string `json:"identifier"`
+	MD5        string `json:"md5"`
+	Type       string `json:"type"`
+}
+
+var dayOneEscapes = regexp.MustCompile(`\\([\\.\-!()\[\]#*_+{}>|` + "`" + `])`)
+
+// DayOne reads a Day One JSON export. path is either the JSON file or the
+// unzipped export folder; media is looked up next to the JSON file in the
+// photos, videos, audios and pdfs folders Day One creates.
+func DayOne(path string) ([]Entry, error) {
+	files, err := dayOneFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read day one export: %w", err)
+		}
+		var export dayOneExport
+		if err := json.Unmarshal(data, &export); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", filepath.Base(file), err)
+		}
+		dir := filepath.Dir(file)
+		for i, raw := range export.Entries {
+			entries = append(entries, dayOneToEntry(raw, dir, fmt.Sprintf("%s#%d", filepath.Base(file), i+1)))
+		}
+	}
+	return entries, nil
+}
+
+func dayOneFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("open day one export: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Day One JSON files in %s", path)
+	}
+	return files, nil
+}
+
+func dayOneToEntry(raw dayOneEntry, dir, source string) Entry {
+	entry := Entry{Source: source, Tags: raw.Tags}
+	if raw.UUID != "" {
+		entry.Source = raw.UUID
+	}
+	if created, err := time.Parse(time.RFC3339, raw.CreationDate); err == nil {
+		if loc, err := time.LoadLocation(raw.TimeZone); err == nil && raw.TimeZone != "" {
+			created = created.In(loc)
+		}
+		entry.Created = created
+	}
+
+	text := dayOneEscapes.ReplaceAllString(raw.Text, "$1")
+	entry.Title, entry.Body = splitTitle(text)
+
+	media := []struct {
+		folder string
+		items  []dayOneMedia
+	}{
+		{"photos", raw.Photos},
+		{"videos", raw.Videos},
+		{"audios", raw.Audios},
+		{"pdfs", raw.PDFs},
+	}
+	for _, kind := range media {
+		for _, item := range kind.items {
+			if item.MD5 == "" {
+				continue
+			}
+			name := item.MD5
+			if item.Type != "" {
+				name += "." + item.Type
+			}
+			attachment := Attachment{Path: filepath.Join(dir, kind.folder, name)}
+			if item.Identifier != "" {
+				attachment.Ref = "dayone-moment://" + item.Identifier
+			}
+			entry.Attachments = append(entry.Attachments, attachment)
+		}
+	}
+	return entry
+}
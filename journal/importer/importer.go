@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/codec"
+)
+
+// Entry is a note read from another journaling tool, before it is written
+// to the journal.
+type Entry struct {
+	Source      string
+	Title       string
+	Body        string
+	Created     time.Time
+	Tags        []string
+	Attachments []Attachment
+}
+
+// Attachment is a file referenced by an imported entry. Ref is the text the
+// entry body uses for it, which is rewritten to the copied file's path.
+type Attachment struct {
+	Path string
+	Ref  string
+}
+
+type Skipped struct {
+	Source string
+	Reason string
+}
+
+type Report struct {
+	Imported   []string
+	Duplicates []string
+	Skipped    []Skipped
+}
+
+type Options struct {
+	DryRun bool
+}
+
+var hashtagInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// Import writes entries through svc, so notes are encrypted when the journal
+// is. An entry whose filename is already taken is reported as a duplicate.
+func Import(svc *journal.Service, entries []Entry, opts Options) (Report, error) {
+	var report Report
+	existing := map[string]bool{}
+	if notes, err := svc.ListNotes(); err == nil {
+		for _, note := range notes {
+			existing[note.Filename] = true
+		}
+	}
+
+	for _, entry := range entries {
+		title := strings.TrimSpace(entry.Title)
+		body := strings.TrimSpace(entry.Body)
+		if title == "" && body == "" {
+			report.Skipped = append(report.Skipped, Skipped{Source: entry.Source, Reason: "empty entry"})
+			continue
+		}
+		if entry.Created.IsZero() {
+			report.Skipped = append(report.Skipped, Skipped{Source: entry.Source, Reason: "no date"})
+			continue
+		}
+		if title == "" {
+			title = entry.Created.Format("Monday 2 January 2006")
+		}
+
+		filename := codec.BuildFilename(title, entry.Created)
+		if existing[filename] {
+			report.Duplicates = append(report.Duplicates, entry.Source)
+			continue
+		}
+		existing[filename] = true
+
+		body = withHashtags(body, entry.Tags)
+		if !opts.DryRun {
+			edit := journal.Edit{Title: title, Body: body + "\n", Created: entry.Created, Attachments: pending(entry.Attachments)}
+			if _, _, err := svc.SaveEdit(edit); err != nil {
+				return report, fmt.Errorf("import %s: %w", entry.Source, err)
+			}
+		}
+		report.Imported = append(report.Imported, filename)
+	}
+	return report, nil
+}
+
+// withHashtags appends tags the body does not already mention, so they are
+// kept by the tag extraction that runs on every save.
+func withHashtags(body string, tags []string) string {
+	present := map[string]bool{}
+	for _, tag := range codec.ExtractTags(body) {
+		present[tag] = true
+	}
+	var missing []string
+	for _, tag := range tags {
+		tag = hashtag(tag)
+		if tag == "" || present[strings.ToLower(tag)] {
+			continue
+		}
+		present[strings.ToLower(tag)] = true
+		missing = append(missing, "#"+tag)
+	}
+	if len(missing) == 0 {
+		return body
+	}
+	return body + "\n\n" + strings.Join(missing, " ")
+}
+
+func hashtag(tag string) string {
+	tag = strings.TrimLeft(strings.TrimSpace(tag), "#@")
+	tag = strings.Join(strings.Fields(tag), "-")
+	tag = hashtagInvalidChars.ReplaceAllString(tag, "")
+	tag = strings.TrimLeft(tag, "0123456789_-")
+	return tag
+}
+
+func pending(attachments []Attachment) []journal.PendingAttachment {
+	var out []journal.PendingAttachment
+	for _, attachment := range attachments {
+		if _, err := os.Stat(attachment.Path); err != nil {
+			continue
+		}
+		out = append(out, journal.PendingAttachment{Source: attachment.Path, Link: attachment.Ref})
+	}
+	return out
+}
+
+// splitTitle uses a leading Markdown heading, or failing that a short first
+// line, as the entry title.
+func splitTitle(text string) (string, string) {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	first, rest, _ := strings.Cut(text, "\n")
+	first = strings.TrimSpace(first)
+	if strings.HasPrefix(first, "#") {
+		heading := strings.TrimSpace(strings.TrimLeft(first, "#"))
+		if heading != "" {
+			return heading, strings.TrimSpace(rest)
+		}
+	}
+	if first != "" && len(first) <= 80 && strings.TrimSpace(rest) != "" {
+		return first, strings.TrimSpace(rest)
+	}
+	return "", text
+}
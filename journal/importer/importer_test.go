@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestDayOneImportCopiesPhotosAndReportsDuplicates(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "Journal.json"), `{
+  "entries": [
+    {
+      "uuid": "A1",
+      "creationDate": "2023-05-06T08:30:00Z",
+      "timeZone": "UTC",
+      "text": "# Beach day\n\nSwam a lot\\. ![](dayone-moment://P1)",
+      "tags": ["summer fun", "travel"],
+      "photos": [{"identifier": "P1", "md5": "abc123", "type": "jpeg"}]
+    },
+    {"uuid": "A2", "creationDate": "2023-05-07T08:30:00Z", "text": ""}
+  ]
+}`)
+	writeFile(t, filepath.Join(src, "photos", "abc123.jpeg"), "jpeg")
+
+	entries, err := DayOne(src)
+	if err != nil {
+		t.Fatalf("DayOne: %v", err)
+	}
+	svc := journal.NewService(t.TempDir())
+	report, err := Import(svc, entries, Options{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if len(report.Imported) != 1 || len(report.Skipped) != 1 {
+		t.Fatalf("report = %+v, want one imported and one skipped", report)
+	}
+
+	note, err := svc.LoadNote(report.Imported[0])
+	if err != nil {
+		t.Fatalf("LoadNote: %v", err)
+	}
+	if note.Title != "Beach day" {
+		t.Fatalf("Title = %q, want Beach day", note.Title)
+	}
+	if strings.Join(note.Tags, ",") != "summer-fun,travel" {
+		t.Fatalf("Tags = %v, want [summer-fun travel]", note.Tags)
+	}
+	if !strings.Contains(note.Content, "Swam a lot.") {
+		t.Fatalf("escapes not removed: %q", note.Content)
+	}
+	link := "attachments/2023-05-06_08-30_Beach_day/abc123.jpeg"
+	if !strings.Contains(note.Content, link) {
+		t.Fatalf("photo link not rewritten: %q", note.Content)
+	}
+	if _, err := os.Stat(filepath.Join(svc.Root, filepath.FromSlash(link))); err != nil {
+		t.Fatalf("photo not copied: %v", err)
+	}
+
+	again, err := Import(svc, entries, Options{})
+	if err != nil {
+		t.Fatalf("second Import: %v", err)
+	}
+	if len(again.Imported) != 0 || len(again.Duplicates) != 1 {
+		t.Fatalf("second report = %+v, want one duplicate", again)
+	}
+}
+
+func TestParseJrnlText(t *testing.T) {
+	text := "[2022-03-04 09:15:00 PM] Long walk. Saw @birds by the river.\nSecond line.\n\n" +
+		"2022-03-05 07:00 Quick note\n"
+	entries := parseJrnlText(text, "journal.txt")
+	if len(entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(entries))
+	}
+	first := entries[0]
+	want := time.Date(2022, 3, 4, 21, 15, 0, 0, time.Local)
+	if !first.Created.Equal(want) {
+		t.Fatalf("Created = %v, want %v", first.Created, want)
+	}
+	if first.Title != "Long walk" {
+		t.Fatalf("Title = %q, want Long walk", first.Title)
+	}
+	if first.Body != "Saw @birds by the river.\nSecond line." {
+		t.Fatalf("Body = %q", first.Body)
+	}
+	if len(first.Tags) != 1 || first.Tags[0] != "birds" {
+		t.Fatalf("Tags = %v, want [birds]", first.Tags)
+	}
+	if entries[1].Title != "Quick note" {
+		t.Fatalf("second Title = %q, want Quick note", entries[1].Title)
+	}
+}
+
+func TestMarkdownDirReadsFrontMatterAndEmbeds(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "daily", "2024-02-10.md"), "---\ntags: [work, focus]\n---\n# Planning\n\nSketch ![[board.png]]\n")
+	writeFile(t, filepath.Join(src, "board.png"), "png")
+	writeFile(t, filepath.Join(src, ".obsidian", "config.md"), "ignored")
+
+	entries, err := MarkdownDir(src)
+	if err != nil {
+		t.Fatalf("MarkdownDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Title != "Planning" {
+		t.Fatalf("Title = %q, want Planning", entry.Title)
+	}
+	if !entry.Created.Equal(time.Date(2024, 2, 10, 0, 0, 0, 0, time.Local)) {
+		t.Fatalf("Created = %v, want 2024-02-10", entry.Created)
+	}
+	if len(entry.Attachments) != 1 || !strings.Contains(entry.Body, "![board.png]("+entry.Attachments[0].Ref+")") {
+		t.Fatalf("embed not converted: %q %+v", entry.Body, entry.Attachments)
+	}
+
+	svc := journal.NewService(t.TempDir())
+	report, err := Import(svc, entries, Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if len(report.Imported) != 1 {
+		t.Fatalf("Imported = %v, want one entry", report.Imported)
+	}
+	if notes, _ := svc.ListNotes(); len(notes) != 0 {
+		t.Fatalf("dry run wrote %d notes", len(notes))
+	}
+}
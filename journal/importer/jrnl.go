@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type jrnlExport struct {
+	Entries []jrnlEntry `json:"entries"`
+}
+
+type jrnlEntry struct {
+	Title string   `json:"title"`
+	Body  string   `json:"body"`
+	Date  string   `json:"date"`
+	Time  string   `json:"time"`
+	Tags  []string `json:"tags"`
+}
+
+var (
+	jrnlHeader      = regexp.MustCompile(`^\[?(\d{4}-\d{2}-\d{2}[ T]\d{1,2}:\d{2}(?::\d{2})?(?: ?[AaPp][Mm])?)\]?\s+(.*)$`)
+	jrnlTag         = regexp.MustCompile(`(?:^|\s)@([A-Za-z][A-Za-z0-9_-]*)`)
+	jrnlTimeLayouts = []string{
+		"2006-01-02 03:04:05 PM",
+		"2006-01-02 03:04:05PM",
+		"2006-01-02 03:04 PM",
+		"2006-01-02 03:04PM",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+	}
+)
+
+// Jrnl reads a jrnl export, either the JSON format (`jrnl --export json`)
+// or the plain text journal file. jrnl @tags become a7 tags.
+func Jrnl(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jrnl export: %w", err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return parseJrnlJSON(data, filepath.Base(path))
+	}
+	return parseJrnlText(string(data), filepath.Base(path)), nil
+}
+
+func parseJrnlJSON(data []byte, source string) ([]Entry, error) {
+	var export jrnlExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", source, err)
+	}
+	entries := make([]Entry, 0, len(export.Entries))
+	for i, raw := range export.Entries {
+		entry := Entry{
+			Source:  fmt.Sprintf("%s#%d", source, i+1),
+			Title:   strings.TrimSpace(raw.Title),
+			Body:    raw.Body,
+			Created: parseJrnlTime(strings.TrimSpace(raw.Date + " " + raw.Time)),
+		}
+		entry.Tags = append(entry.Tags, raw.Tags...)
+		entry.Tags = append(entry.Tags, jrnlTags(raw.Title+"\n"+raw.Body)...)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseJrnlText splits a plain text jrnl journal into entries. Each entry
+// starts with a timestamp line; like jrnl, the first sentence is the title.
+func parseJrnlText(text, source string) []Entry {
+	var entries []Entry
+	var current *Entry
+	var body []string
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Body = strings.TrimSpace(strings.Join(body, "\n"))
+		current.Tags = jrnlTags(current.Title + "\n" + current.Body)
+		entries = append(entries, *current)
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		match := jrnlHeader.FindStringSubmatch(line)
+		if match != nil {
+			if created := parseJrnlTime(match[1]); !created.IsZero() {
+				flush()
+				title, rest := splitSentence(match[2])
+				current = &Entry{
+					Source:  fmt.Sprintf("%s:%s", source, match[1]),
+					Title:   title,
+					Created: created,
+				}
+				body = body[:0]
+				if rest != "" {
+					body = append(body, rest)
+				}
+				continue
+			}
+		}
+		if current != nil {
+			body = append(body, line)
+		}
+	}
+	flush()
+	return entries
+}
+
+func parseJrnlTime(value string) time.Time {
+	value = strings.Replace(value, "T", " ", 1)
+	for _, layout := range jrnlTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, strings.ToUpper(value), time.Local); err == nil {
+			return parsed
+		}
+	}
+	if parsed, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return parsed
+	}
+	return time.Time{}
+}
+
+func splitSentence(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	for i, r := range line {
+		if r != '.' && r != '?' && r != '!' {
+			continue
+		}
+		if i+1 == len(line) || line[i+1] == ' ' {
+			return strings.TrimSpace(strings.TrimSuffix(line[:i+1], ".")), strings.TrimSpace(line[i+1:])
+		}
+	}
+	return line, ""
+}
+
+func jrnlTags(text string) []string {
+	var tags []string
+	for _, match := range jrnlTag.FindAllStringSubmatch(text, -1) {
+		tags = append(tags, match[1])
+	}
+	return tags
+}
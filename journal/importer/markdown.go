@@ -0,0 +1,199 @@
+package importer
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	markdownImageLink = regexp.MustCompile(`!\[[^\]]*\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	wikiEmbed         = regexp.MustCompile(`!\[\[([^\]|#]+)(?:[|#][^\]]*)?\]\]`)
+	filenameDate      = regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})`)
+	frontMatterDates  = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+)
+
+// MarkdownDir reads every Markdown file below dir, such as an Obsidian vault
+// of daily notes. Dates come from YAML front matter, a YYYY-MM-DD in the
+// file name, or the file's modification time, in that order. Embedded local
+// images and files are imported as attachments.
+func MarkdownDir(dir string) ([]Entry, error) {
+	var entries []Entry
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+		entry, err := readMarkdownFile(dir, path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read markdown folder: %w", err)
+	}
+	return entries, nil
+}
+
+func readMarkdownFile(root, path string) (Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	rel, _ := filepath.Rel(root, path)
+	entry := Entry{Source: rel}
+
+	meta, text := splitYAMLFrontMatter(string(data))
+	if title, ok := meta["title"].(string); ok {
+		entry.Title = strings.TrimSpace(title)
+	}
+	for _, key := range []string{"created", "date"} {
+		if created := metaTime(meta[key]); !created.IsZero() {
+			entry.Created = created
+			break
+		}
+	}
+	entry.Tags = metaTags(meta["tags"])
+
+	if entry.Title == "" {
+		entry.Title, text = splitHeading(text)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if entry.Title == "" {
+		entry.Title = name
+	}
+	if entry.Created.IsZero() {
+		if match := filenameDate.FindString(name); match != "" {
+			entry.Created, _ = time.ParseInLocation("2006-01-02", match, time.Local)
+		}
+	}
+	if entry.Created.IsZero() {
+		if info, err := os.Stat(path); err == nil {
+			entry.Created = info.ModTime()
+		}
+	}
+
+	entry.Body, entry.Attachments = markdownAttachments(root, filepath.Dir(path), strings.TrimSpace(text))
+	return entry, nil
+}
+
+func splitYAMLFrontMatter(text string) (map[string]any, string) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return nil, text
+	}
+	end := strings.Index(text[4:], "\n---")
+	if end < 0 {
+		return nil, text
+	}
+	meta := map[string]any{}
+	if err := yaml.Unmarshal([]byte(text[4:4+end]), &meta); err != nil {
+		return nil, text
+	}
+	rest := text[4+end+4:]
+	return meta, strings.TrimPrefix(rest, "\n")
+}
+
+func splitHeading(text string) (string, string) {
+	trimmed := strings.TrimSpace(text)
+	first, rest, _ := strings.Cut(trimmed, "\n")
+	if strings.HasPrefix(first, "# ") {
+		return strings.TrimSpace(first[2:]), rest
+	}
+	return "", text
+}
+
+func metaTime(value any) time.Time {
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case string:
+		for _, layout := range frontMatterDates {
+			if parsed, err := time.ParseInLocation(layout, strings.TrimSpace(v), time.Local); err == nil {
+				return parsed
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func metaTags(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })
+	case []any:
+		tags := make([]string, 0, len(v))
+		for _, item := range v {
+			if tag, ok := item.(string); ok {
+				tags = append(tags, tag)
+			}
+		}
+		return tags
+	}
+	return nil
+}
+
+// markdownAttachments finds embedded local files, resolving them against the
+// note's folder and then the root folder. Each reference is replaced with a
+// placeholder that Import swaps for the copied file's path, and Obsidian
+// ![[embeds]] become standard Markdown images.
+func markdownAttachments(root, dir, body string) (string, []Attachment) {
+	var attachments []Attachment
+	refs := map[string]string{}
+	attach := func(ref string) string {
+		ref, _ = url.PathUnescape(ref)
+		for _, base := range []string{dir, root} {
+			path := filepath.Join(base, filepath.FromSlash(ref))
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			if placeholder, ok := refs[path]; ok {
+				return placeholder
+			}
+			placeholder := fmt.Sprintf("a7-import://%d/%s", len(attachments), url.PathEscape(filepath.Base(path)))
+			refs[path] = placeholder
+			attachments = append(attachments, Attachment{Path: path, Ref: placeholder})
+			return placeholder
+		}
+		return ""
+	}
+
+	body = wikiEmbed.ReplaceAllStringFunc(body, func(embed string) string {
+		name := strings.TrimSpace(wikiEmbed.FindStringSubmatch(embed)[1])
+		placeholder := attach(name)
+		if placeholder == "" {
+			return embed
+		}
+		return fmt.Sprintf("![%s](%s)", filepath.Base(name), placeholder)
+	})
+	body = markdownImageLink.ReplaceAllStringFunc(body, func(link string) string {
+		ref := markdownImageLink.FindStringSubmatch(link)[1]
+		if strings.Contains(ref, "://") || strings.HasPrefix(ref, "/") {
+			return link
+		}
+		placeholder := attach(ref)
+		if placeholder == "" {
+			return link
+		}
+		return strings.Replace(link, "("+ref, "("+placeholder, 1)
+	})
+	return body, attachments
+}
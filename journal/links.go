@@ -0,0 +1,135 @@
+package journal
+
+import (
+	"strings"
+
+	"github.com/never00rei/a7/journal/codec"
+)
+
+type RenameReport struct {
+	Filename string
+	Updated  []string
+	Skipped  []string
+}
+
+// ResolveLink finds the note a [[target]] refers to, by title first and then
+// by note ID, ignoring case.
+func ResolveLink(notes []NoteInfo, target string) (NoteInfo, bool) {
+	target = strings.TrimSpace(target)
+	for _, note := range notes {
+		if strings.EqualFold(note.Title, target) {
+			return note, true
+		}
+	}
+	for _, note := range notes {
+		if strings.EqualFold(NoteID(note.Filename), NoteID(target)) {
+			return note, true
+		}
+	}
+	return NoteInfo{}, false
+}
+
+// Links returns the notes that body links to, skipping links that do not
+// resolve.
+func Links(notes []NoteInfo, body string) []NoteInfo {
+	var out []NoteInfo
+	seen := map[string]bool{}
+	for _, link := range codec.ParseWikiLinks(body) {
+		note, ok := ResolveLink(notes, link.Target)
+		if !ok || seen[note.Filename] {
+			continue
+		}
+		seen[note.Filename] = true
+		out = append(out, note)
+	}
+	return out
+}
+
+// Backlinks lists the notes whose bodies link to filename. Encrypted notes
+// that cannot be decrypted are skipped.
+func (s *Service) Backlinks(filename string) ([]NoteInfo, error) {
+	notes, err := s.ListNotes()
+	if err != nil {
+		return nil, err
+	}
+	var out []NoteInfo
+	for _, info := range notes {
+		if info.Filename == filename {
+			continue
+		}
+		note, err := s.LoadNote(info.Filename)
+		if err != nil {
+			continue
+		}
+		for _, linked := range Links(notes, note.Content) {
+			if linked.Filename == filename {
+				out = append(out, info)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+// RenameNote changes a note's title and rewrites [[links]] to the old title
+// across the journal. The filename, and with it the note's attachment
+// folder, stays the same. Every rewritten note, the renamed one included,
+// passes through the save hooks, and notes that cannot be decrypted are
+// reported as skipped rather than failing the rename.
+func (s *Service) RenameNote(filename, newTitle string) (RenameReport, error) {
+	report := RenameReport{Filename: filename}
+	note, err := s.LoadNote(filename)
+	if err != nil {
+		return report, err
+	}
+	newTitle = strings.TrimSpace(newTitle)
+	if newTitle == "" || newTitle == note.Title {
+		return report, nil
+	}
+	if report, err = s.rewriteLinks(filename, note.Title, newTitle); err != nil {
+		return report, err
+	}
+	matter := codec.FrontMatter{Title: newTitle, Created: note.Created, Prompt: note.Prompt, Tags: note.Tags}
+	if _, err := s.writeNoteFile(filename, matter, note.Content, note.Encrypted, nil); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// rewriteLinks points [[links]] to a note's old title at its new one,
+// leaving the note itself to the caller.
+func (s *Service) rewriteLinks(filename, oldTitle, newTitle string) (RenameReport, error) {
+	report := RenameReport{Filename: filename}
+	if newTitle == "" || newTitle == oldTitle {
+		return report, nil
+	}
+	notes, err := s.ListNotes()
+	if err != nil {
+		return report, err
+	}
+	target := oldTitle
+	if resolved, ok := ResolveLink(notes, oldTitle); !ok || resolved.Filename != filename {
+		// Another note owns the old title, so only ID links point here.
+		target = NoteID(filename)
+	}
+	for _, info := range notes {
+		if info.Filename == filename {
+			continue
+		}
+		note, err := s.LoadNote(info.Filename)
+		if err != nil {
+			report.Skipped = append(report.Skipped, info.Filename)
+			continue
+		}
+		updated := codec.ReplaceWikiLinkTarget(note.Content, target, newTitle)
+		if updated == note.Content {
+			continue
+		}
+		noteMatter := codec.FrontMatter{Title: note.Title, Created: note.Created, Prompt: note.Prompt, Tags: note.Tags}
+		if _, err := s.writeNoteFile(info.Filename, noteMatter, updated, note.Encrypted, nil); err != nil {
+			return report, err
+		}
+		report.Updated = append(report.Updated, info.Filename)
+	}
+	return report, nil
+}
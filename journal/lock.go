@@ -0,0 +1,67 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/never00rei/a7/journal/lock"
+)
+
+const (
+	// LockFile marks a journal as open in an a7 session.
+	LockFile = ".a7.lock"
+	// NoteLocksDir holds the short-lived locks taken while a note is written.
+	NoteLocksDir = ".locks"
+)
+
+// Note locks are only held for the length of a write, so one older than
+// noteLockStale was left by a process that died mid-save.
+var (
+	noteLockWait  = 2 * time.Second
+	noteLockStale = 30 * time.Second
+)
+
+// LockJournal marks the journal as open by this process. When another live
+// a7 already has it open the returned error is a *lock.HeldError; the
+// journal can still be used, since every save takes its own note lock.
+func (s *Service) LockJournal() (*lock.Lock, error) {
+	return lock.Acquire(filepath.Join(s.Root, LockFile), lock.Options{Purpose: "another a7"})
+}
+
+// UnlockJournal removes the journal lock and any note locks whoever holds
+// them, for locks left by an a7 on another machine that cannot be recovered
+// automatically. It returns the holder of the journal lock, if there was one.
+func (s *Service) UnlockJournal() (lock.Info, error) {
+	path := filepath.Join(s.Root, LockFile)
+	info, _ := lock.Read(path)
+	if err := lock.Remove(path); err != nil {
+		return info, err
+	}
+	if err := os.RemoveAll(filepath.Join(s.Root, NoteLocksDir)); err != nil {
+		return info, fmt.Errorf("remove note locks: %w", err)
+	}
+	return info, nil
+}
+
+// writeFile writes a note while holding its note lock, so two processes
+// saving the same note never interleave.
+func (s *Service) writeFile(filename, content string) error {
+	return s.withNoteLock(filename, "save", func() error {
+		return s.store.Write(filename, content)
+	})
+}
+
+func (s *Service) withNoteLock(filename, op string, fn func() error) error {
+	held, err := lock.Acquire(filepath.Join(s.Root, NoteLocksDir, filename+".lock"), lock.Options{
+		Purpose:    "another a7 writing " + filename,
+		StaleAfter: noteLockStale,
+		Wait:       noteLockWait,
+	})
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", op, filename, err)
+	}
+	defer held.Release()
+	return fn()
+}
@@ -0,0 +1,186 @@
+// Package lock implements advisory lock files that let several a7
+// processes share a journal folder. A lock file records who holds it, so a
+// lock left behind by a process that has exited can be recovered.
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Info identifies the process holding a lock.
+type Info struct {
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Purpose string    `json:"purpose,omitempty"`
+	Since   time.Time `json:"since"`
+}
+
+func (i Info) String() string {
+	return fmt.Sprintf("pid %d on %s since %s", i.PID, i.Host, i.Since.Local().Format("15:04 Jan 2"))
+}
+
+// HeldError reports a lock held by another live process.
+type HeldError struct {
+	Path string
+	Info Info
+}
+
+func (e *HeldError) Error() string {
+	if e.Info.PID == 0 {
+		return "locked by another process"
+	}
+	what := e.Info.Purpose
+	if what == "" {
+		what = "another process"
+	}
+	return fmt.Sprintf("locked by %s (%s)", what, e.Info)
+}
+
+// Options control when a lock counts as stale and how long to wait for it.
+// A lock is stale when its process is gone from this host, or when it is
+// older than StaleAfter; zero means never by age.
+type Options struct {
+	Purpose    string
+	StaleAfter time.Duration
+	Wait       time.Duration
+}
+
+type Lock struct {
+	Path string
+	Info Info
+}
+
+var (
+	pollInterval    = 25 * time.Millisecond
+	unreadableAfter = 5 * time.Second
+)
+
+// Acquire creates the lock file at path, recovering stale locks and waiting
+// up to opts.Wait for a live one to be released. A lock still held when the
+// wait ends is reported as a *HeldError.
+func Acquire(path string, opts Options) (*Lock, error) {
+	info := current(opts.Purpose)
+	deadline := time.Now().Add(opts.Wait)
+	for {
+		err := create(path, info)
+		if err == nil {
+			return &Lock{Path: path, Info: info}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		holder, readErr := Read(path)
+		if errors.Is(readErr, fs.ErrNotExist) {
+			continue
+		}
+		if readErr == nil && Stale(holder, opts.StaleAfter) || readErr != nil && unreadableStale(path) {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("remove stale lock: %w", err)
+			}
+			continue
+		}
+		if readErr != nil {
+			holder = Info{Purpose: opts.Purpose}
+		}
+		if time.Now().After(deadline) {
+			return nil, &HeldError{Path: path, Info: holder}
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Release removes the lock if this process still owns it.
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	holder, err := Read(l.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err == nil && (holder.PID != l.Info.PID || holder.Host != l.Info.Host || !holder.Since.Equal(l.Info.Since)) {
+		return nil
+	}
+	if err := os.Remove(l.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("release lock: %w", err)
+	}
+	return nil
+}
+
+// Read returns the holder recorded in a lock file.
+func Read(path string) (Info, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Info{}, err
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return Info{}, fmt.Errorf("read lock %s: %w", filepath.Base(path), err)
+	}
+	return info, nil
+}
+
+// Stale reports whether a lock can be taken over: its process has exited,
+// or it is older than staleAfter.
+func Stale(info Info, staleAfter time.Duration) bool {
+	if staleAfter > 0 && time.Since(info.Since) > staleAfter {
+		return true
+	}
+	if info.Host == hostname() {
+		return info.PID != os.Getpid() && !processAlive(info.PID)
+	}
+	return false
+}
+
+// unreadableStale covers lock files left empty or truncated by a crash. A
+// fresh unreadable lock is probably still being written, so it is only
+// recovered once it has been that way for a while.
+func unreadableStale(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && time.Since(info.ModTime()) > unreadableAfter
+}
+
+// Remove deletes a lock file whoever holds it.
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove lock: %w", err)
+	}
+	return nil
+}
+
+func create(path string, info Info) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create lock dir: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	err = json.NewEncoder(file).Encode(info)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("write lock: %w", err)
+	}
+	return nil
+}
+
+func current(purpose string) Info {
+	return Info{PID: os.Getpid(), Host: hostname(), Purpose: purpose, Since: time.Now()}
+}
+
+func hostname() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return host
+}
@@ -0,0 +1,75 @@
+package lock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquireReportsHolderAndWaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.lock")
+	held, err := Acquire(path, Options{Purpose: "first"})
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	_, err = Acquire(path, Options{Purpose: "second"})
+	var heldErr *HeldError
+	if !errors.As(err, &heldErr) {
+		t.Fatalf("expected HeldError, got %v", err)
+	}
+	if heldErr.Info.PID != os.Getpid() || heldErr.Info.Purpose != "first" {
+		t.Fatalf("holder = %+v", heldErr.Info)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		held.Release()
+	}()
+	next, err := Acquire(path, Options{Purpose: "second", Wait: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("Acquire after release: %v", err)
+	}
+	if info, err := Read(path); err != nil || info.Purpose != "second" {
+		t.Fatalf("Read = %+v, %v", info, err)
+	}
+
+	// A lock taken over by someone else is left alone on release.
+	if err := held.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("lock removed by previous holder: %v", err)
+	}
+	if err := next.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("lock not removed: %v", err)
+	}
+}
+
+func TestAcquireRecoversStaleLocks(t *testing.T) {
+	dir := t.TempDir()
+
+	dead := filepath.Join(dir, "dead.lock")
+	if err := create(dead, Info{PID: 1 << 30, Host: hostname(), Since: time.Now()}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := Acquire(dead, Options{}); err != nil {
+		t.Fatalf("Acquire over exited process: %v", err)
+	}
+
+	old := filepath.Join(dir, "old.lock")
+	if err := create(old, Info{PID: 1, Host: "elsewhere", Since: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := Acquire(old, Options{}); err == nil {
+		t.Fatalf("expected lock from another host to be kept without StaleAfter")
+	}
+	if _, err := Acquire(old, Options{StaleAfter: time.Minute}); err != nil {
+		t.Fatalf("Acquire over old lock: %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package lock
+
+import "os"
+
+// processAlive relies on FindProcess opening a handle to the process, which
+// fails on Windows when no process has that id.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	process.Release()
+	return true
+}
@@ -0,0 +1,101 @@
+package journal
+
+import (
+	"strings"
+	"time"
+
+	"github.com/never00rei/a7/journal/codec"
+)
+
+type MigratedNote struct {
+	Filename  string
+	Title     string
+	Created   time.Time
+	WordCount int
+}
+
+type MigrationReport struct {
+	Scanned   int
+	Migrated  []MigratedNote
+	BackupDir string
+}
+
+// MigrateLegacy rewrites notes that still use the `# 2006-01-02_15-04 Title`
+// header, or have no header at all, into front matter notes. Originals are
+// copied to a timestamped folder under .backup first, and each note keeps its
+// modification time so the dashboard order does not change.
+func (s *Service) MigrateLegacy(dryRun bool) (MigrationReport, error) {
+	var report MigrationReport
+	entries, err := s.store.ListMarkdown()
+	if err != nil {
+		return report, err
+	}
+
+	backupSet := time.Now().Format("2006-01-02_15-04-05")
+	for _, entry := range entries {
+		report.Scanned++
+		content, modTime, err := s.store.Read(entry.Filename)
+		if err != nil {
+			return report, err
+		}
+		if matter, _ := codec.ParseFrontMatter(content); hasFrontMatter(matter) {
+			continue
+		}
+
+		title, created, body := codec.ParseHeaderIn(content, s.location)
+		if title == "" && created.IsZero() {
+			body = content
+		}
+		if title == "" {
+			title = titleFromFilename(entry.Filename)
+		}
+		if created.IsZero() {
+			if parsed, ok := codec.ParseFilenameTimestampIn(entry.Filename, s.location); ok {
+				created = parsed
+			} else {
+				created = modTime
+			}
+		}
+		matter := codec.FrontMatter{
+			Title:     title,
+			Created:   created,
+			Updated:   modTime,
+			WordCount: codec.CountWords(body),
+			Tags:      codec.NormalizeTags(codec.ExtractTags(body)),
+		}
+		report.Migrated = append(report.Migrated, MigratedNote{
+			Filename:  entry.Filename,
+			Title:     title,
+			Created:   created,
+			WordCount: matter.WordCount,
+		})
+		if dryRun {
+			continue
+		}
+
+		if report.BackupDir, err = s.store.Backup(backupSet, entry.Filename, content); err != nil {
+			return report, err
+		}
+		if err := s.writeFile(entry.Filename, codec.RenderContent(matter, body)); err != nil {
+			return report, err
+		}
+		if !modTime.IsZero() {
+			if err := s.store.SetModTime(entry.Filename, modTime); err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, nil
+}
+
+func hasFrontMatter(matter codec.FrontMatter) bool {
+	return matter.Title != "" || !matter.Created.IsZero() || !matter.Updated.IsZero() || matter.Encrypted || matter.WordCount >= 0
+}
+
+func titleFromFilename(filename string) string {
+	name := strings.TrimSuffix(filename, ".md")
+	if _, ok := codec.ParseFilenameTimestamp(name); ok {
+		name = strings.TrimPrefix(name[len(codec.TimestampLayout):], "_")
+	}
+	return strings.TrimSpace(strings.ReplaceAll(name, "_", " "))
+}
@@ -0,0 +1,209 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/never00rei/a7/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const AnyCategory = "any"
+
+var ErrNoPrompts = errors.New("no prompts available")
+
+type Prompt struct {
+	Text     string
+	Category string
+}
+
+var builtins = map[string][]string{
+	"reflection": {
+		"What is taking up most of your attention right now?",
+		"What would you tell yourself from a year ago?",
+		"What did today teach you?",
+		"Which decision this week are you most unsure about, and why?",
+	},
+	"gratitude": {
+		"What made you smile today?",
+		"Who helped you recently, and how?",
+		"What is something ordinary you would miss if it were gone?",
+	},
+	"growth": {
+		"What skill are you building, and what is the next small step?",
+		"What did you avoid today, and what would it take to face it?",
+		"Where did you get stuck this week, and what got you unstuck?",
+	},
+	"creativity": {
+		"Describe the room you are in as if it were a scene in a novel.",
+		"What idea keeps coming back to you lately?",
+		"If you had a free day tomorrow, how would you spend it?",
+	},
+}
+
+func Builtin() []Prompt {
+	return fromCategories(builtins)
+}
+
+// Load returns the built-in prompts plus any found in dir. Plain text files
+// hold one prompt per line and use the file name as the category; YAML files
+// map category names to lists of prompts.
+func Load(dir string) ([]Prompt, error) {
+	all := Builtin()
+	if dir == "" {
+		return all, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return all, nil
+		}
+		return nil, fmt.Errorf("list prompts: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".txt" && ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("load prompts: %w", err)
+		}
+		switch ext {
+		case ".txt":
+			all = append(all, ParseText(strings.TrimSuffix(name, filepath.Ext(name)), string(content))...)
+		default:
+			parsed, err := ParseYAML(content)
+			if err != nil {
+				return nil, fmt.Errorf("load prompts %s: %w", name, err)
+			}
+			all = append(all, parsed...)
+		}
+	}
+
+	return dedupe(all), nil
+}
+
+func ParseText(category, content string) []Prompt {
+	var out []Prompt
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		out = append(out, Prompt{Text: line, Category: normaliseCategory(category)})
+	}
+	return out
+}
+
+func ParseYAML(content []byte) ([]Prompt, error) {
+	categories := map[string][]string{}
+	if err := yaml.Unmarshal(content, &categories); err != nil {
+		return nil, err
+	}
+	return fromCategories(categories), nil
+}
+
+func Categories(all []Prompt) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, prompt := range all {
+		if seen[prompt.Category] {
+			continue
+		}
+		seen[prompt.Category] = true
+		out = append(out, prompt.Category)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Pick chooses a random prompt from category, skipping any whose text is in
+// recent. If every candidate was used recently the recent list is ignored.
+func Pick(all []Prompt, category string, recent []string) (Prompt, error) {
+	var candidates []Prompt
+	for _, prompt := range all {
+		if category == "" || category == AnyCategory || prompt.Category == category {
+			candidates = append(candidates, prompt)
+		}
+	}
+	if len(candidates) == 0 {
+		return Prompt{}, ErrNoPrompts
+	}
+
+	used := make(map[string]bool, len(recent))
+	for _, text := range recent {
+		used[strings.TrimSpace(text)] = true
+	}
+	fresh := make([]string, 0, len(candidates))
+	for _, prompt := range candidates {
+		if !used[prompt.Text] {
+			fresh = append(fresh, prompt.Text)
+		}
+	}
+	if len(fresh) == 0 {
+		for _, prompt := range candidates {
+			fresh = append(fresh, prompt.Text)
+		}
+	}
+
+	text := utils.RandomStringFromSlice(fresh)
+	for _, prompt := range candidates {
+		if prompt.Text == text {
+			return prompt, nil
+		}
+	}
+	return Prompt{}, ErrNoPrompts
+}
+
+func fromCategories(categories map[string][]string) []Prompt {
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []Prompt
+	for _, name := range names {
+		for _, text := range categories[name] {
+			text = strings.TrimSpace(text)
+			if text == "" {
+				continue
+			}
+			out = append(out, Prompt{Text: text, Category: normaliseCategory(name)})
+		}
+	}
+	return out
+}
+
+func dedupe(all []Prompt) []Prompt {
+	seen := map[string]bool{}
+	out := make([]Prompt, 0, len(all))
+	for _, prompt := range all {
+		key := prompt.Category + "\x00" + prompt.Text
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, prompt)
+	}
+	return out
+}
+
+func normaliseCategory(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "general"
+	}
+	return name
+}
@@ -0,0 +1,66 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadReadsTextAndYAML(t *testing.T) {
+	dir := t.TempDir()
+	text := "# comments are ignored\nWhat shipped today?\n\nWhat blocked you?\n"
+	if err := os.WriteFile(filepath.Join(dir, "Work.txt"), []byte(text), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	yml := "family:\n  - Who did you call this week?\n"
+	if err := os.WriteFile(filepath.Join(dir, "more.yaml"), []byte(yml), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	counts := map[string]int{}
+	for _, prompt := range loaded {
+		counts[prompt.Category]++
+	}
+	if counts["work"] != 2 {
+		t.Fatalf("work prompts = %d, want 2", counts["work"])
+	}
+	if counts["family"] != 1 {
+		t.Fatalf("family prompts = %d, want 1", counts["family"])
+	}
+	if counts["gratitude"] == 0 {
+		t.Fatalf("built-in prompts missing")
+	}
+}
+
+func TestPickAvoidsRecentPrompts(t *testing.T) {
+	all := []Prompt{
+		{Text: "one", Category: "a"},
+		{Text: "two", Category: "a"},
+		{Text: "three", Category: "b"},
+	}
+	for i := 0; i < 20; i++ {
+		prompt, err := Pick(all, "a", []string{"one"})
+		if err != nil {
+			t.Fatalf("Pick: %v", err)
+		}
+		if prompt.Text != "two" {
+			t.Fatalf("Pick = %q, want %q", prompt.Text, "two")
+		}
+	}
+
+	prompt, err := Pick(all, "b", []string{"three"})
+	if err != nil {
+		t.Fatalf("Pick exhausted: %v", err)
+	}
+	if prompt.Text != "three" {
+		t.Fatalf("Pick exhausted = %q, want fallback %q", prompt.Text, "three")
+	}
+
+	if _, err := Pick(all, "missing", nil); err != ErrNoPrompts {
+		t.Fatalf("Pick missing err = %v, want %v", err, ErrNoPrompts)
+	}
+}
@@ -0,0 +1,160 @@
+package journal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/never00rei/a7/journal/codec"
+	"github.com/never00rei/a7/journal/crypto"
+)
+
+type RekeyReport struct {
+	Notes         int
+	Attachments   int
+	Drafts        int
+	SkippedDrafts []string
+	BackupDir     string
+}
+
+// Rewritten reports whether any note or attachment was re-encrypted. Those
+// need the new key even when Rekey stopped partway with an error.
+func (r RekeyReport) Rewritten() bool {
+	return r.Notes > 0 || r.Attachments > 0
+}
+
+type rekeyedNote struct {
+	filename string
+	original string
+	modTime  time.Time
+	matter   codec.FrontMatter
+	body     string
+}
+
+// Rekey re-encrypts every encrypted note, attachment and draft for the SSH
+// key at newKeyPath. Everything is checked to decrypt before anything is
+// written, so a key that cannot open the journal leaves it untouched.
+// Originals of notes and attachments are copied to a timestamped folder
+// under .backup and each file is replaced through a temporary file. If a
+// write fails partway, the report counts the files already re-encrypted.
+// Drafts that cannot be read or rewritten are left as they are and listed
+// in SkippedDrafts rather than stopping the rekey. The service uses the new
+// key afterwards.
+func (s *Service) Rekey(newKeyPath string) (RekeyReport, error) {
+	var report RekeyReport
+	probe, err := crypto.EncryptBody("a7", newKeyPath)
+	if err == nil {
+		_, err = crypto.DecryptBody(probe, newKeyPath)
+	}
+	if err != nil {
+		return report, fmt.Errorf("new key: %w", err)
+	}
+
+	entries, err := s.store.ListMarkdown()
+	if err != nil {
+		return report, err
+	}
+	var notes []rekeyedNote
+	var attachments []Attachment
+	for _, entry := range entries {
+		content, modTime, err := s.store.Read(entry.Filename)
+		if err != nil {
+			return report, err
+		}
+		if matter, remaining := codec.ParseFrontMatter(content); matter.Encrypted {
+			body, err := crypto.DecryptBody(remaining, s.SSHKeyPath)
+			if err != nil {
+				return report, fmt.Errorf("decrypt %s: %w", entry.Filename, err)
+			}
+			notes = append(notes, rekeyedNote{filename: entry.Filename, original: content, modTime: modTime, matter: matter, body: body})
+		}
+
+		listed, err := s.ListAttachments(entry.Filename)
+		if err != nil {
+			return report, err
+		}
+		for _, attachment := range listed {
+			if !attachment.Encrypted {
+				continue
+			}
+			if err := s.decryptAttachment(io.Discard, attachment); err != nil {
+				return report, err
+			}
+			attachments = append(attachments, attachment)
+		}
+	}
+	drafts, unreadable, err := s.ListDrafts()
+	if err != nil {
+		return report, err
+	}
+	report.SkippedDrafts = unreadable
+
+	backupSet := time.Now().Format("2006-01-02_15-04-05") + "-rekey"
+	for _, note := range notes {
+		if report.BackupDir, err = s.store.Backup(backupSet, note.filename, note.original); err != nil {
+			return report, err
+		}
+	}
+	for _, attachment := range attachments {
+		if report.BackupDir, err = s.store.BackupFile(backupSet, attachment.Path); err != nil {
+			return report, err
+		}
+	}
+
+	for _, note := range notes {
+		encrypted, err := crypto.EncryptBody(note.body, newKeyPath)
+		if err != nil {
+			return report, err
+		}
+		if err := s.writeFile(note.filename, codec.RenderContent(note.matter, encrypted)); err != nil {
+			return report, err
+		}
+		report.Notes++
+		if err := s.store.SetModTime(note.filename, note.modTime); err != nil {
+			return report, err
+		}
+	}
+	for _, attachment := range attachments {
+		if err := s.rekeyAttachment(attachment, newKeyPath); err != nil {
+			return report, err
+		}
+		report.Attachments++
+	}
+
+	s.SSHKeyPath = newKeyPath
+	for _, draft := range drafts {
+		if err := s.SaveDraft(draft); err != nil {
+			report.SkippedDrafts = append(report.SkippedDrafts, draft.ID)
+			continue
+		}
+		report.Drafts++
+	}
+	return report, nil
+}
+
+func (s *Service) decryptAttachment(dst io.Writer, attachment Attachment) error {
+	in, err := s.store.OpenFile(attachment.Path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := crypto.DecryptStream(dst, in, s.SSHKeyPath); err != nil {
+		return fmt.Errorf("decrypt %s: %w", attachment.Path, err)
+	}
+	return nil
+}
+
+func (s *Service) rekeyAttachment(attachment Attachment, newKeyPath string) error {
+	var plain bytes.Buffer
+	if err := s.decryptAttachment(&plain, attachment); err != nil {
+		return err
+	}
+	err := s.store.Replace(attachment.Path, func(w io.Writer) error {
+		return crypto.EncryptStream(w, &plain, newKeyPath)
+	})
+	if err != nil {
+		return fmt.Errorf("encrypt %s: %w", attachment.Path, err)
+	}
+	return nil
+}
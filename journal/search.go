@@ -0,0 +1,88 @@
+package journal
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const snippetRunes = 160
+
+// SearchResult is a note matching a search. Line is the 1-based body line
+// the snippet comes from, or 0 when only the title or tags matched.
+type SearchResult struct {
+	Note    NoteInfo
+	Locked  bool
+	Line    int
+	Snippet string
+}
+
+// Search finds notes whose title, tags and body together contain every word
+// of query, ignoring case, newest first. Encrypted notes that cannot be
+// decrypted are matched on their title and tags only and reported as locked.
+func (s *Service) Search(query string) ([]SearchResult, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	notes, err := s.ListNotes()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for _, info := range notes {
+		result := SearchResult{Note: info}
+		body := ""
+		if note, err := s.LoadNote(info.Filename); err != nil {
+			if note == nil {
+				return nil, err
+			}
+			result.Locked = true
+		} else {
+			body = note.Content
+		}
+		meta := strings.ToLower(info.Title + " " + strings.Join(info.Tags, " "))
+		lower := strings.ToLower(body)
+		if !containsAll(meta+"\n"+lower, terms) {
+			continue
+		}
+		result.Line, result.Snippet = firstMatchingLine(body, terms)
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, _ := results[i].Note.Date()
+		b, _ := results[j].Note.Date()
+		return a.After(b)
+	})
+	return results, nil
+}
+
+func containsAll(text string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func firstMatchingLine(body string, terms []string) (int, string) {
+	for i, line := range strings.Split(body, "\n") {
+		lower := strings.ToLower(line)
+		for _, term := range terms {
+			if strings.Contains(lower, term) {
+				return i + 1, snippet(strings.TrimSpace(line))
+			}
+		}
+	}
+	return 0, ""
+}
+
+func snippet(line string) string {
+	if utf8.RuneCountInString(line) <= snippetRunes {
+		return line
+	}
+	runes := []rune(line)
+	return string(runes[:snippetRunes-1]) + "…"
+}
@@ -1,14 +1,26 @@
 package journal
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"slices"
 	"time"
 
 	"github.com/never00rei/a7/journal/codec"
 	"github.com/never00rei/a7/journal/crypto"
+	"github.com/never00rei/a7/journal/hooks"
 	"github.com/never00rei/a7/journal/store"
 )
 
+var (
+	// ErrNotFound is wrapped by errors for notes that do not exist.
+	ErrNotFound = errors.New("note not found")
+	// ErrDecrypt is wrapped by errors for encrypted notes the configured key
+	// cannot open.
+	ErrDecrypt = errors.New("decrypt note")
+)
+
 type NoteInfo struct {
 	Filename  string
 	ModTime   time.Time
@@ -17,6 +29,8 @@ type NoteInfo struct {
 	Updated   time.Time
 	Encrypted bool
 	WordCount int
+	Prompt    string
+	Tags      []string
 }
 
 type Note struct {
@@ -28,6 +42,8 @@ type Note struct {
 	Updated   time.Time
 	Encrypted bool
 	WordCount int
+	Prompt    string
+	Tags      []string
 }
 
 type Service struct {
@@ -35,12 +51,16 @@ type Service struct {
 	Encrypt    bool
 	SSHKeyPath string
 	store      *store.FS
+	hooks      *hooks.Runner
+	location   *time.Location
 }
 
 type Option func(*Service)
 
+type NoteOption func(*codec.FrontMatter)
+
 func NewService(root string, opts ...Option) *Service {
-	svc := &Service{Root: root, store: store.NewFS(root)}
+	svc := &Service{Root: root, store: store.NewFS(root), location: time.Local}
 	for _, opt := range opts {
 		opt(svc)
 	}
@@ -54,6 +74,50 @@ func WithEncryption(enabled bool, sshKeyPath string) Option {
 	}
 }
 
+// WithHooks runs the user's hook scripts when notes are saved, deleted and
+// opened.
+func WithHooks(runner *hooks.Runner) Option {
+	return func(s *Service) {
+		s.hooks = runner
+	}
+}
+
+func WithPrompt(prompt string) NoteOption {
+	return func(matter *codec.FrontMatter) {
+		matter.Prompt = prompt
+	}
+}
+
+func WithTags(tags ...string) NoteOption {
+	return func(matter *codec.FrontMatter) {
+		matter.Tags = append(matter.Tags, tags...)
+	}
+}
+
+// ReplaceTags sets a note's front matter tags, dropping the ones it had.
+func ReplaceTags(tags ...string) NoteOption {
+	return func(matter *codec.FrontMatter) {
+		matter.Tags = append([]string(nil), tags...)
+	}
+}
+
+// WithLocation sets the time zone legacy header and filename timestamps are
+// read in, which is the local zone by default.
+func WithLocation(loc *time.Location) Option {
+	return func(s *Service) {
+		s.location = loc
+	}
+}
+
+// Date returns the day a note was written, falling back to the timestamp in
+// its filename for notes without created metadata.
+func (n NoteInfo) Date() (time.Time, bool) {
+	if !n.Created.IsZero() {
+		return n.Created, true
+	}
+	return codec.ParseFilenameTimestamp(n.Filename)
+}
+
 func (s *Service) ListNotes() ([]NoteInfo, error) {
 	entries, err := s.store.ListMarkdown()
 	if err != nil {
@@ -62,34 +126,53 @@ func (s *Service) ListNotes() ([]NoteInfo, error) {
 
 	notes := make([]NoteInfo, 0, len(entries))
 	for _, entry := range entries {
-		info := NoteInfo{
-			Filename:  entry.Filename,
-			ModTime:   entry.ModTime,
-			WordCount: -1,
-		}
-		content, _, err := s.store.Read(entry.Filename)
+		info, err := s.noteInfo(entry)
 		if err != nil {
 			return nil, err
 		}
-		matter, _ := codec.ParseFrontMatter(content)
-		if matter.Title != "" || !matter.Created.IsZero() || !matter.Updated.IsZero() || matter.Encrypted || matter.WordCount >= 0 {
-			info.Title = matter.Title
-			info.Created = matter.Created
-			info.Updated = matter.Updated
-			info.Encrypted = matter.Encrypted
-			info.WordCount = matter.WordCount
-		} else {
-			info.Title, info.Created, _ = codec.ParseHeader(content)
-		}
 		notes = append(notes, info)
 	}
 	return notes, nil
 }
 
+// StatNote reads the listing metadata for one note, as ListNotes would.
+func (s *Service) StatNote(filename string) (NoteInfo, error) {
+	entry, err := s.store.Stat(filename)
+	if err != nil {
+		return NoteInfo{}, notFound(err)
+	}
+	return s.noteInfo(entry)
+}
+
+func (s *Service) noteInfo(entry store.NoteInfo) (NoteInfo, error) {
+	info := NoteInfo{
+		Filename:  entry.Filename,
+		ModTime:   entry.ModTime,
+		WordCount: -1,
+	}
+	content, _, err := s.store.Read(entry.Filename)
+	if err != nil {
+		return NoteInfo{}, err
+	}
+	matter, _ := codec.ParseFrontMatter(content)
+	if hasFrontMatter(matter) {
+		info.Title = matter.Title
+		info.Created = matter.Created
+		info.Updated = matter.Updated
+		info.Encrypted = matter.Encrypted
+		info.WordCount = matter.WordCount
+		info.Prompt = matter.Prompt
+		info.Tags = matter.Tags
+	} else {
+		info.Title, info.Created, _ = codec.ParseHeaderIn(content, s.location)
+	}
+	return info, nil
+}
+
 func (s *Service) LoadNote(filename string) (*Note, error) {
 	content, modTime, err := s.store.Read(filename)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 
 	note := &Note{
@@ -105,10 +188,12 @@ func (s *Service) LoadNote(filename string) (*Note, error) {
 		note.Updated = matter.Updated
 		note.Encrypted = matter.Encrypted
 		note.WordCount = matter.WordCount
+		note.Prompt = matter.Prompt
+		note.Tags = matter.Tags
 		if matter.Encrypted {
 			decrypted, err := crypto.DecryptBody(remaining, s.SSHKeyPath)
 			if err != nil {
-				return note, fmt.Errorf("decrypt note: %w", err)
+				return note, fmt.Errorf("%w: %w", ErrDecrypt, err)
 			}
 			note.Content = decrypted
 		} else {
@@ -117,43 +202,144 @@ func (s *Service) LoadNote(filename string) (*Note, error) {
 		return note, nil
 	}
 
-	note.Title, note.Created, note.Content = codec.ParseHeader(content)
+	note.Title, note.Created, note.Content = codec.ParseHeaderIn(content, s.location)
 	return note, nil
 }
 
-func (s *Service) SaveNote(title, body string, created time.Time) (string, error) {
+// notFound marks store errors for missing notes with ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return err
+}
+
+func (s *Service) SaveNote(title, body string, created time.Time, opts ...NoteOption) (string, error) {
+	filename, _, err := s.SaveEdit(Edit{Title: title, Body: body, Created: created, Options: opts})
+	return filename, err
+}
+
+// UpdateNote rewrites a note, keeping the tags set in its front matter.
+// Tags that came from #hashtags in the old body are dropped and taken from
+// the new body instead, so removing a hashtag removes the tag.
+func (s *Service) UpdateNote(filename, title, body string, created time.Time, opts ...NoteOption) error {
+	_, _, err := s.SaveEdit(Edit{Filename: filename, Title: title, Body: body, Created: created, Options: opts})
+	return err
+}
+
+// Edit is a note the user wrote. Filename is empty for a new note. Rename
+// rewrites [[links]] to the note's old title when Title changes.
+type Edit struct {
+	Filename    string
+	Title       string
+	Body        string
+	Created     time.Time
+	Options     []NoteOption
+	Rename      bool
+	Attachments []PendingAttachment
+}
+
+// SaveEdit writes an edit as one save. The pre-save hooks run first, so a
+// rejected save leaves the journal as it was; only then is the note renamed,
+// its attachments stored and the note written. It returns the note's
+// filename and the body written, which the hooks may have changed.
+func (s *Service) SaveEdit(edit Edit) (string, string, error) {
+	created := edit.Created
 	if created.IsZero() {
 		created = time.Now()
 	}
-
-	updated := time.Now()
-	filename := codec.BuildFilename(title, created)
-	contentBody, encrypted, err := crypto.MaybeEncryptBody(body, s.Encrypt, s.SSHKeyPath)
+	filename := edit.Filename
+	matter := codec.FrontMatter{}
+	if filename == "" {
+		filename = codec.BuildFilename(edit.Title, created)
+	} else if existing, _, err := s.store.Read(filename); err == nil {
+		var oldBody string
+		matter, oldBody = codec.ParseFrontMatter(existing)
+		if !matter.Encrypted {
+			matter.Tags = removeTags(matter.Tags, codec.ExtractTags(oldBody))
+		}
+	}
+	matter.Title = edit.Title
+	matter.Created = created
+	for _, opt := range edit.Options {
+		opt(&matter)
+	}
+	var oldTitle string
+	rename := edit.Rename && edit.Filename != ""
+	if rename {
+		info, err := s.StatNote(filename)
+		if err != nil {
+			return "", "", err
+		}
+		oldTitle = info.Title
+	}
+	body, err := s.writeNoteFile(filename, matter, edit.Body, s.Encrypt, func(body string) (string, error) {
+		if rename {
+			if _, err := s.rewriteLinks(filename, oldTitle, edit.Title); err != nil {
+				return "", err
+			}
+		}
+		return s.attachPending(filename, body, edit.Attachments)
+	})
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+	return filename, body, nil
+}
+
+// writeNoteFile saves a note, running the save hooks around it. prepare,
+// when given, runs once the pre-save hooks have accepted the body and may
+// change it before it is written. It returns the body written.
+func (s *Service) writeNoteFile(filename string, matter codec.FrontMatter, body string, encrypt bool, prepare func(string) (string, error)) (string, error) {
+	matter.Encrypted = encrypt
+	if s.hooks != nil {
+		var err error
+		if body, err = s.hooks.PreSave(s.hookPayload(filename, matter, body)); err != nil {
+			return "", err
+		}
+	}
+	if prepare != nil {
+		var err error
+		if body, err = prepare(body); err != nil {
+			return "", err
+		}
 	}
-	wordCount := codec.CountWords(body)
-	if err := s.writeNoteFile(filename, title, contentBody, created, updated, encrypted, wordCount); err != nil {
+	if err := s.writeNote(filename, matter, body, encrypt); err != nil {
 		return "", err
 	}
-
-	return filename, nil
+	if s.hooks != nil {
+		s.hooks.Notify(hooks.PostSave, s.hookPayload(filename, matter, body))
+	}
+	return body, nil
 }
 
-func (s *Service) UpdateNote(filename, title, body string, created time.Time) error {
-	if created.IsZero() {
-		created = time.Now()
-	}
-	updated := time.Now()
-	contentBody, encrypted, err := crypto.MaybeEncryptBody(body, s.Encrypt, s.SSHKeyPath)
+// writeNote saves body with the given encryption setting. Changes the user
+// did not make in the editor, like link rewrites, keep a note's current
+// encryption state.
+func (s *Service) writeNote(filename string, matter codec.FrontMatter, body string, encrypt bool) error {
+	contentBody, encrypted, err := crypto.MaybeEncryptBody(body, encrypt, s.SSHKeyPath)
 	if err != nil {
 		return err
 	}
-	wordCount := codec.CountWords(body)
-	return s.writeNoteFile(filename, title, contentBody, created, updated, encrypted, wordCount)
+	matter.Updated = time.Now()
+	matter.Encrypted = encrypted
+	matter.WordCount = codec.CountWords(body)
+	if !encrypted {
+		// Hashtags in an encrypted body stay inside it rather than being
+		// copied into the plain text front matter.
+		matter.Tags = append(matter.Tags, codec.ExtractTags(body)...)
+	}
+	matter.Tags = codec.NormalizeTags(matter.Tags)
+	content := codec.RenderContent(matter, contentBody)
+	return s.writeFile(filename, content)
 }
 
-func (s *Service) writeNoteFile(filename, title, body string, created, updated time.Time, encrypted bool, wordCount int) error {
-	content := codec.RenderContent(title, body, created, updated, encrypted, wordCount)
-	return s.store.Write(filename, content)
+func removeTags(tags, remove []string) []string {
+	var kept []string
+	for _, tag := range codec.NormalizeTags(tags) {
+		if !slices.Contains(remove, tag) {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
 }
@@ -1,12 +1,21 @@
 package journal
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/never00rei/a7/journal/codec"
+	"github.com/never00rei/a7/journal/hooks"
+	"github.com/never00rei/a7/journal/lock"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestSaveLoadAndListNotes(t *testing.T) {
@@ -148,3 +157,681 @@ func TestListNotesLoadsMetadata(t *testing.T) {
 		t.Fatalf("WordCount = %d, want %d", info.WordCount, codec.CountWords("hello world"))
 	}
 }
+
+func TestPromptIsRecordedAndPreservedOnUpdate(t *testing.T) {
+	root := t.TempDir()
+	svc := NewService(root)
+
+	created := time.Date(2024, 5, 6, 7, 8, 0, 0, time.UTC)
+	filename, err := svc.SaveNote("Prompted", "answer", created, WithPrompt("What did today teach you?"))
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	if err := svc.UpdateNote(filename, "Prompted", "a longer answer", created); err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+
+	loaded, err := svc.LoadNote(filename)
+	if err != nil {
+		t.Fatalf("LoadNote: %v", err)
+	}
+	if loaded.Prompt != "What did today teach you?" {
+		t.Fatalf("Prompt = %q, want recorded prompt", loaded.Prompt)
+	}
+	if loaded.WordCount != 3 {
+		t.Fatalf("WordCount = %d, want 3", loaded.WordCount)
+	}
+}
+
+func TestSaveNoteRecordsHashtags(t *testing.T) {
+	root := t.TempDir()
+	svc := NewService(root)
+
+	body := "# Heading\n\nWorked on #A7 with #work.\n\n```sh\n#notatag\n```\n"
+	if _, err := svc.SaveNote("Tagged", body, time.Now(), WithTags("Imported")); err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+
+	notes, err := svc.ListNotes()
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	want := []string{"a7", "imported", "work"}
+	if len(notes) != 1 || len(notes[0].Tags) != len(want) {
+		t.Fatalf("Tags = %v, want %v", notes[0].Tags, want)
+	}
+	for i, tag := range want {
+		if notes[0].Tags[i] != tag {
+			t.Fatalf("Tags = %v, want %v", notes[0].Tags, want)
+		}
+	}
+}
+
+func TestUpdateNoteKeepsFrontMatterTags(t *testing.T) {
+	root := t.TempDir()
+	svc := NewService(root)
+	filename, err := svc.SaveNote("Tagged", "About #work", time.Now(), WithTags("imported"))
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	if err := svc.UpdateNote(filename, "Tagged", "About #home", time.Now()); err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	note, err := svc.LoadNote(filename)
+	if err != nil {
+		t.Fatalf("LoadNote: %v", err)
+	}
+	if got := strings.Join(note.Tags, ","); got != "home,imported" {
+		t.Fatalf("Tags = %q, want home,imported", got)
+	}
+}
+
+func TestEncryptedNoteKeepsHashtagsOutOfFrontMatter(t *testing.T) {
+	root := t.TempDir()
+	svc := NewService(root, WithEncryption(true, writeTestSSHKey(t)))
+	filename, err := svc.SaveNote("Private", "Saw the #doctor", time.Now(), WithTags("health"))
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(root, filename))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if strings.Contains(string(content), "doctor") || !strings.Contains(string(content), "health") {
+		t.Fatalf("front matter should hold only the given tags:\n%s", content)
+	}
+}
+
+func TestSortNotes(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	notes := []NoteInfo{
+		{Filename: "b.md", Title: "banana", Created: base.AddDate(0, 0, 2), Updated: base.AddDate(0, 0, 3), WordCount: 5},
+		{Filename: "a.md", Title: "Apple", Created: base.AddDate(0, 0, 1), Updated: base.AddDate(0, 0, 9), WordCount: 50},
+		{Filename: "c.md", Title: "cherry", Created: base.AddDate(0, 0, 3), ModTime: base.AddDate(0, 0, 1), WordCount: 1},
+	}
+
+	order := func() string {
+		out := ""
+		for _, note := range notes {
+			out += note.Filename[:1]
+		}
+		return out
+	}
+
+	cases := []struct {
+		field      SortField
+		descending bool
+		want       string
+	}{
+		{SortCreated, false, "abc"},
+		{SortCreated, true, "cba"},
+		{SortUpdated, true, "abc"},
+		{SortTitle, false, "abc"},
+		{SortWords, true, "abc"},
+		{SortWords, false, "cba"},
+	}
+	for _, tc := range cases {
+		SortNotes(notes, tc.field, tc.descending)
+		if got := order(); got != tc.want {
+			t.Fatalf("SortNotes(%s, desc=%t) = %q, want %q", tc.field, tc.descending, got, tc.want)
+		}
+	}
+}
+
+func TestMigrateLegacyRewritesHeaderNotes(t *testing.T) {
+	zone := time.FixedZone("UTC+5", 5*60*60)
+	root := t.TempDir()
+	svc := NewService(root, WithLocation(zone))
+	legacy := "2023-04-05_06-07_Old_Note.md"
+	if err := os.WriteFile(filepath.Join(root, legacy), []byte("# 2023-04-05_06-07 Old Note\n\nthree word body #legacy\n"), 0644); err != nil {
+		t.Fatalf("write legacy note: %v", err)
+	}
+	if _, err := svc.SaveNote("Modern", "already migrated", time.Now()); err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+
+	dry, err := svc.MigrateLegacy(true)
+	if err != nil {
+		t.Fatalf("MigrateLegacy dry run: %v", err)
+	}
+	if len(dry.Migrated) != 1 || dry.BackupDir != "" {
+		t.Fatalf("dry run report = %+v, want one note and no backup", dry)
+	}
+
+	report, err := svc.MigrateLegacy(false)
+	if err != nil {
+		t.Fatalf("MigrateLegacy: %v", err)
+	}
+	if report.Scanned != 2 || len(report.Migrated) != 1 || report.Migrated[0].Filename != legacy {
+		t.Fatalf("report = %+v, want only %s migrated", report, legacy)
+	}
+	if _, err := os.Stat(filepath.Join(report.BackupDir, legacy)); err != nil {
+		t.Fatalf("backup missing: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, legacy))
+	if err != nil {
+		t.Fatalf("read migrated: %v", err)
+	}
+	matter, body := codec.ParseFrontMatter(string(content))
+	if matter.Title != "Old Note" || matter.WordCount != 4 {
+		t.Fatalf("front matter = %+v, want title Old Note and 4 words", matter)
+	}
+	if want := time.Date(2023, 4, 5, 6, 7, 0, 0, zone); !matter.Created.Equal(want) {
+		t.Fatalf("Created = %v, want %v", matter.Created, want)
+	}
+	if len(matter.Tags) != 1 || matter.Tags[0] != "legacy" {
+		t.Fatalf("Tags = %v, want [legacy]", matter.Tags)
+	}
+	if body != "three word body #legacy\n" {
+		t.Fatalf("body = %q", body)
+	}
+	if notes, _ := svc.ListNotes(); len(notes) != 2 {
+		t.Fatalf("backup folder should not be listed as a note, got %d notes", len(notes))
+	}
+
+	again, err := svc.MigrateLegacy(false)
+	if err != nil {
+		t.Fatalf("second MigrateLegacy: %v", err)
+	}
+	if len(again.Migrated) != 0 {
+		t.Fatalf("second run migrated %v, want nothing", again.Migrated)
+	}
+}
+
+func writeTestSSHKey(t *testing.T) string {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(private, "")
+	if err != nil {
+		t.Fatalf("MarshalPrivateKey: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return path
+}
+
+func TestAddAttachmentEncryptsForEncryptedJournal(t *testing.T) {
+	root := t.TempDir()
+	svc := NewService(root, WithEncryption(true, writeTestSSHKey(t)))
+	filename, err := svc.SaveNote("Trip", "photos below", time.Now())
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	src := filepath.Join(t.TempDir(), "beach photo.png")
+	if err := os.WriteFile(src, []byte("png bytes"), 0644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	attachment, err := svc.AddAttachment(filename, src)
+	if err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+	if !attachment.Encrypted || !strings.HasSuffix(attachment.Path, ".png.age") {
+		t.Fatalf("attachment = %+v, want encrypted .age file", attachment)
+	}
+	if want := "[beach photo.png](attachments/" + NoteID(filename) + "/beach%20photo.png.age)"; attachment.Link() != want {
+		t.Fatalf("Link = %q, want %q", attachment.Link(), want)
+	}
+	stored, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(attachment.Path)))
+	if err != nil {
+		t.Fatalf("read stored: %v", err)
+	}
+	if strings.Contains(string(stored), "png bytes") {
+		t.Fatalf("attachment stored in plain text")
+	}
+
+	listed, err := svc.ListAttachments(filename)
+	if err != nil || len(listed) != 1 || listed[0].Name != "beach photo.png" {
+		t.Fatalf("ListAttachments = %+v, %v", listed, err)
+	}
+	plain, err := svc.ExtractAttachment(listed[0], t.TempDir())
+	if err != nil {
+		t.Fatalf("ExtractAttachment: %v", err)
+	}
+	if data, _ := os.ReadFile(plain); string(data) != "png bytes" {
+		t.Fatalf("extracted = %q, want original bytes", data)
+	}
+}
+
+func TestBacklinksAndLinkAwareRename(t *testing.T) {
+	root := t.TempDir()
+	svc := NewService(root)
+	created := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	target, err := svc.SaveNote("Garden Plans", "tomatoes", created)
+	if err != nil {
+		t.Fatalf("SaveNote target: %v", err)
+	}
+	linking, err := svc.SaveNote("Weekend", "Worked on [[garden plans|the plan]] and [[Missing]]", created.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SaveNote linking: %v", err)
+	}
+	code, err := svc.SaveNote("Snippets", "```\n[[Garden Plans]]\n```", created.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("SaveNote code: %v", err)
+	}
+
+	backlinks, err := svc.Backlinks(target)
+	if err != nil {
+		t.Fatalf("Backlinks: %v", err)
+	}
+	if len(backlinks) != 1 || backlinks[0].Filename != linking {
+		t.Fatalf("Backlinks = %+v, want only %s", backlinks, linking)
+	}
+
+	report, err := svc.RenameNote(target, "Vegetable Garden")
+	if err != nil {
+		t.Fatalf("RenameNote: %v", err)
+	}
+	if report.Filename != target || len(report.Updated) != 1 || report.Updated[0] != linking {
+		t.Fatalf("report = %+v, want %s updated", report, linking)
+	}
+	renamed, err := svc.LoadNote(target)
+	if err != nil || renamed.Title != "Vegetable Garden" || renamed.Content != "tomatoes" {
+		t.Fatalf("renamed note = %+v, %v", renamed, err)
+	}
+	updated, err := svc.LoadNote(linking)
+	if err != nil {
+		t.Fatalf("LoadNote linking: %v", err)
+	}
+	if updated.Content != "Worked on [[Vegetable Garden|the plan]] and [[Missing]]" {
+		t.Fatalf("linking content = %q", updated.Content)
+	}
+	untouched, _ := svc.LoadNote(code)
+	if !strings.Contains(untouched.Content, "[[Garden Plans]]") {
+		t.Fatalf("link in code block was rewritten: %q", untouched.Content)
+	}
+}
+
+func TestListAndToggleTasks(t *testing.T) {
+	svc := NewService(t.TempDir())
+	older := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	body := "Plans\n- [ ] follow up with Sam\n- [x] book tickets\n```\n- [ ] not a task\n```\n"
+	filename, err := svc.SaveNote("Monday", body, older)
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	if _, err := svc.SaveNote("Tuesday", "* [ ] water plants", older.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+
+	tasks, err := svc.ListTasks()
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("tasks = %+v, want 3", tasks)
+	}
+	if tasks[0].Text != "water plants" || tasks[1].Text != "follow up with Sam" || !tasks[2].Done {
+		t.Fatalf("tasks out of order or misparsed: %+v", tasks)
+	}
+
+	if err := svc.ToggleTask(tasks[1]); err != nil {
+		t.Fatalf("ToggleTask: %v", err)
+	}
+	note, err := svc.LoadNote(filename)
+	if err != nil {
+		t.Fatalf("LoadNote: %v", err)
+	}
+	if !strings.Contains(note.Content, "- [x] follow up with Sam") {
+		t.Fatalf("task not checked: %q", note.Content)
+	}
+	if err := svc.ToggleTask(Task{Note: tasks[1].Note, Line: tasks[1].Line, Text: "something else"}); err == nil {
+		t.Fatalf("ToggleTask with stale text should fail")
+	}
+}
+
+func TestDraftsAreEncryptedAndRecoverable(t *testing.T) {
+	root := t.TempDir()
+	svc := NewService(root, WithEncryption(true, writeTestSSHKey(t)))
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	draft := Draft{Title: "Half written", Body: "secret thoughts", Created: created}
+	if err := svc.SaveDraft(draft); err != nil {
+		t.Fatalf("SaveDraft: %v", err)
+	}
+
+	id := DraftID("", created)
+	raw, err := os.ReadFile(filepath.Join(root, DraftsDir, id+".draft"))
+	if err != nil {
+		t.Fatalf("read draft: %v", err)
+	}
+	if strings.Contains(string(raw), "secret") {
+		t.Fatalf("draft stored in plain text: %q", raw)
+	}
+	if notes, _ := svc.ListNotes(); len(notes) != 0 {
+		t.Fatalf("drafts listed as notes: %v", notes)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, DraftsDir, "broken.draft"), []byte(`{"title":`), 0644); err != nil {
+		t.Fatalf("write broken draft: %v", err)
+	}
+	drafts, unreadable, err := svc.ListDrafts()
+	if err != nil || len(drafts) != 1 {
+		t.Fatalf("ListDrafts = %v, %v", drafts, err)
+	}
+	if len(unreadable) != 1 || unreadable[0] != "broken" {
+		t.Fatalf("unreadable drafts = %v, want broken", unreadable)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(root, DraftsDir, ".*.tmp")); len(leftovers) != 0 {
+		t.Fatalf("temporary files left behind: %v", leftovers)
+	}
+	if drafts[0].ID != id || drafts[0].Body != "secret thoughts" || !drafts[0].Created.Equal(created) {
+		t.Fatalf("draft = %+v", drafts[0])
+	}
+
+	if err := svc.DiscardDraft(id); err != nil {
+		t.Fatalf("DiscardDraft: %v", err)
+	}
+	if drafts, _, _ := svc.ListDrafts(); len(drafts) != 0 {
+		t.Fatalf("drafts after discard = %v", drafts)
+	}
+}
+
+func TestRekeyReencryptsNotesAttachmentsAndDrafts(t *testing.T) {
+	root := t.TempDir()
+	oldKey := writeTestSSHKey(t)
+	newKey := writeTestSSHKey(t)
+	svc := NewService(root, WithEncryption(true, oldKey))
+	filename, err := svc.SaveNote("Private", "only for me", time.Now())
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	src := filepath.Join(t.TempDir(), "scan.txt")
+	if err := os.WriteFile(src, []byte("scan"), 0644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	attachment, err := svc.AddAttachment(filename, src)
+	if err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+	if err := svc.SaveDraft(Draft{File: filename, Title: "Private", Body: "more", Created: time.Now()}); err != nil {
+		t.Fatalf("SaveDraft: %v", err)
+	}
+	stale := NewService(root, WithEncryption(true, writeTestSSHKey(t)))
+	if err := stale.SaveDraft(Draft{Title: "Stale", Body: "other key", Created: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatalf("SaveDraft: %v", err)
+	}
+
+	report, err := svc.Rekey(newKey)
+	if err != nil {
+		t.Fatalf("Rekey: %v", err)
+	}
+	if report.Notes != 1 || report.Attachments != 1 || report.Drafts != 1 || report.BackupDir == "" {
+		t.Fatalf("report = %+v", report)
+	}
+	if len(report.SkippedDrafts) != 1 || !strings.HasPrefix(report.SkippedDrafts[0], "new-") {
+		t.Fatalf("skipped drafts = %v, want the stale draft", report.SkippedDrafts)
+	}
+	if _, err := os.Stat(filepath.Join(report.BackupDir, filepath.FromSlash(attachment.Path))); err != nil {
+		t.Fatalf("attachment backup missing: %v", err)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(root, AttachmentsDir, "*", ".*.tmp")); len(leftovers) != 0 {
+		t.Fatalf("temporary files left behind: %v", leftovers)
+	}
+
+	fresh := NewService(root, WithEncryption(true, newKey))
+	note, err := fresh.LoadNote(filename)
+	if err != nil || note.Content != "only for me" {
+		t.Fatalf("LoadNote with new key = %v, %v", note, err)
+	}
+	extracted, err := fresh.ExtractAttachment(attachment, t.TempDir())
+	if err != nil {
+		t.Fatalf("ExtractAttachment with new key: %v", err)
+	}
+	if data, _ := os.ReadFile(extracted); string(data) != "scan" {
+		t.Fatalf("attachment = %q", data)
+	}
+	if drafts, _, err := fresh.ListDrafts(); err != nil || len(drafts) != 1 {
+		t.Fatalf("ListDrafts with new key = %v, %v", drafts, err)
+	}
+	if _, err := NewService(root, WithEncryption(true, oldKey)).LoadNote(filename); err == nil {
+		t.Fatalf("old key still decrypts the note")
+	}
+}
+
+func TestWatchReportsNoteChanges(t *testing.T) {
+	root := t.TempDir()
+	svc := NewService(root)
+	old, err := svc.SaveNote("Old", "going away", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	watcher, err := svc.Watch(50 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer watcher.Close()
+
+	added, err := svc.SaveNote("New", "from another process", time.Now())
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	if err := os.Remove(filepath.Join(root, old)); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := svc.SaveDraft(Draft{Title: "draft", Created: time.Now()}); err != nil {
+		t.Fatalf("SaveDraft: %v", err)
+	}
+
+	got := map[string]bool{}
+	timeout := time.After(5 * time.Second)
+	for len(got) < 2 {
+		select {
+		case batch := <-watcher.Changes:
+			for _, change := range batch {
+				got[change.Filename] = change.Removed
+			}
+		case err := <-watcher.Errors:
+			t.Fatalf("watch error: %v", err)
+		case <-timeout:
+			t.Fatalf("timed out waiting for changes, got %v", got)
+		}
+	}
+	if removed, ok := got[added]; !ok || removed {
+		t.Fatalf("added note not reported as changed: %v", got)
+	}
+	if removed, ok := got[old]; !ok || !removed {
+		t.Fatalf("deleted note not reported as removed: %v", got)
+	}
+	if len(got) != 2 {
+		t.Fatalf("unexpected changes reported: %v", got)
+	}
+
+	info, err := svc.StatNote(added)
+	if err != nil || info.Title != "New" {
+		t.Fatalf("StatNote = %+v, %v", info, err)
+	}
+}
+
+func TestSaveWaitsForNoteLock(t *testing.T) {
+	svc := NewService(t.TempDir())
+	filename, err := svc.SaveNote("Shared", "first", time.Now())
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+
+	wait := noteLockWait
+	noteLockWait = 50 * time.Millisecond
+	t.Cleanup(func() { noteLockWait = wait })
+
+	held, err := lock.Acquire(filepath.Join(svc.Root, NoteLocksDir, filename+".lock"), lock.Options{Purpose: "another a7"})
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	err = svc.UpdateNote(filename, "Shared", "second", time.Now())
+	var heldErr *lock.HeldError
+	if !errors.As(err, &heldErr) {
+		t.Fatalf("expected HeldError, got %v", err)
+	}
+
+	held.Release()
+	if err := svc.UpdateNote(filename, "Shared", "second", time.Now()); err != nil {
+		t.Fatalf("UpdateNote after release: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(svc.Root, NoteLocksDir, filename+".lock")); !os.IsNotExist(err) {
+		t.Fatalf("note lock left behind: %v", err)
+	}
+}
+
+func TestSearchMatchesTitleTagsAndBody(t *testing.T) {
+	svc := NewService(t.TempDir())
+	if _, err := svc.SaveNote("Garden", "Planted tomatoes\nand basil by the fence", time.Now().Add(-time.Hour), WithTags("home")); err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	if _, err := svc.SaveNote("Work", "Basil for lunch", time.Now()); err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+
+	results, err := svc.Search("BASIL")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 || results[0].Note.Title != "Work" {
+		t.Fatalf("results = %+v", results)
+	}
+	if results[1].Line != 2 || results[1].Snippet != "and basil by the fence" {
+		t.Fatalf("garden match = line %d %q", results[1].Line, results[1].Snippet)
+	}
+
+	results, err = svc.Search("home basil")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].Note.Title != "Garden" {
+		t.Fatalf("results = %+v", results)
+	}
+}
+
+func TestHooksRunAroundSaveOpenAndDelete(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+	root := t.TempDir()
+	dir := t.TempDir()
+	log := filepath.Join(t.TempDir(), "events.log")
+	script := "#!/bin/sh\necho \"$A7_EVENT $A7_NOTE_TITLE\" >> " + log + "\n"
+	for _, name := range []string{"post-save", "on-open", "post-delete"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+			t.Fatalf("write hook: %v", err)
+		}
+	}
+	veto := "#!/bin/sh\nif grep -q forbidden; then echo 'no forbidden words' >&2; exit 1; fi\n"
+	if err := os.WriteFile(filepath.Join(dir, "pre-save"), []byte(veto), 0755); err != nil {
+		t.Fatalf("write hook: %v", err)
+	}
+	svc := NewService(root, WithHooks(hooks.NewRunner(dir)))
+
+	if _, err := svc.SaveNote("Banned", "a forbidden word", time.Now()); !errors.Is(err, hooks.ErrRejected) {
+		t.Fatalf("expected veto, got %v", err)
+	}
+	if notes, _ := svc.ListNotes(); len(notes) != 0 {
+		t.Fatalf("vetoed note was written: %+v", notes)
+	}
+
+	filename, err := svc.SaveNote("Kept", "fine words", time.Now())
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	src := filepath.Join(t.TempDir(), "photo.txt")
+	if err := os.WriteFile(src, []byte("pixels"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := svc.AddAttachment(filename, src); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+	if _, err := svc.OpenNote(filename); err != nil {
+		t.Fatalf("OpenNote: %v", err)
+	}
+	backup, err := svc.DeleteNote(filename)
+	if err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(backup, filename)); err != nil {
+		t.Fatalf("note not in backup: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(backup, AttachmentsDir, NoteID(filename), "photo.txt")); err != nil {
+		t.Fatalf("attachment not in backup: %v", err)
+	}
+	if _, err := svc.StatNote(filename); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("note still present: %v", err)
+	}
+
+	events, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	if got := strings.TrimSpace(string(events)); got != "post-save Kept\non-open Kept\npost-delete Kept" {
+		t.Fatalf("events = %q", got)
+	}
+}
+
+func TestRejectedEditLeavesRenameAndAttachmentsUndone(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+	root := t.TempDir()
+	dir := t.TempDir()
+	veto := "#!/bin/sh\nif grep -q forbidden; then exit 1; fi\n"
+	if err := os.WriteFile(filepath.Join(dir, "pre-save"), []byte(veto), 0755); err != nil {
+		t.Fatalf("write hook: %v", err)
+	}
+	log := filepath.Join(t.TempDir(), "saves.log")
+	if err := os.WriteFile(filepath.Join(dir, "post-save"), []byte("#!/bin/sh\necho \"$A7_NOTE_TITLE\" >> "+log+"\n"), 0755); err != nil {
+		t.Fatalf("write hook: %v", err)
+	}
+	svc := NewService(root, WithHooks(hooks.NewRunner(dir)))
+	now := time.Now()
+	target, err := svc.SaveNote("Target", "plain", now)
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	source, err := svc.SaveNote("Source", "see [[Target]]", now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	src := filepath.Join(t.TempDir(), "photo.txt")
+	if err := os.WriteFile(src, []byte("pixels"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	placeholder := Attachment{Name: "photo.txt", Path: "attachments/pending/1/photo.txt"}
+	edit := Edit{
+		Filename:    target,
+		Title:       "Renamed",
+		Body:        "forbidden " + placeholder.Link(),
+		Rename:      true,
+		Attachments: []PendingAttachment{{Source: src, Link: placeholder.URL()}},
+	}
+	if _, _, err := svc.SaveEdit(edit); !errors.Is(err, hooks.ErrRejected) {
+		t.Fatalf("expected veto, got %v", err)
+	}
+	if info, _ := svc.StatNote(target); info.Title != "Target" {
+		t.Fatalf("rejected edit renamed the note to %q", info.Title)
+	}
+	if note, _ := svc.LoadNote(source); note.Content != "see [[Target]]" {
+		t.Fatalf("rejected edit rewrote links: %q", note.Content)
+	}
+	if attachments, _ := svc.ListAttachments(target); len(attachments) != 0 {
+		t.Fatalf("rejected edit stored attachments: %v", attachments)
+	}
+
+	os.Remove(log)
+	edit.Body = "fine " + placeholder.Link()
+	_, body, err := svc.SaveEdit(edit)
+	if err != nil {
+		t.Fatalf("SaveEdit: %v", err)
+	}
+	if !strings.Contains(body, "attachments/"+NoteID(target)+"/photo.txt") {
+		t.Fatalf("body = %q, want the stored attachment linked", body)
+	}
+	if note, _ := svc.LoadNote(source); note.Content != "see [[Renamed]]" {
+		t.Fatalf("links not rewritten: %q", note.Content)
+	}
+	if saves, _ := os.ReadFile(log); strings.TrimSpace(string(saves)) != "Source\nRenamed" {
+		t.Fatalf("saves = %q, want the linking note and the renamed note once each", saves)
+	}
+}
@@ -0,0 +1,75 @@
+package journal
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+type SortField string
+
+const (
+	SortUpdated SortField = "updated"
+	SortCreated SortField = "created"
+	SortTitle   SortField = "title"
+	SortWords   SortField = "words"
+)
+
+var SortFields = []SortField{SortUpdated, SortCreated, SortTitle, SortWords}
+
+func ParseSortField(value string) SortField {
+	for _, field := range SortFields {
+		if strings.EqualFold(string(field), strings.TrimSpace(value)) {
+			return field
+		}
+	}
+	return SortUpdated
+}
+
+func (f SortField) Next() SortField {
+	for i, field := range SortFields {
+		if field == f {
+			return SortFields[(i+1)%len(SortFields)]
+		}
+	}
+	return SortFields[0]
+}
+
+// LastUpdated returns the updated front matter time, or the file modification
+// time for notes without one.
+func (n NoteInfo) LastUpdated() time.Time {
+	if !n.Updated.IsZero() {
+		return n.Updated
+	}
+	return n.ModTime
+}
+
+func SortNotes(notes []NoteInfo, field SortField, descending bool) {
+	less := func(a, b NoteInfo) bool {
+		switch field {
+		case SortCreated:
+			aDate, _ := a.Date()
+			bDate, _ := b.Date()
+			return aDate.Before(bDate)
+		case SortTitle:
+			return strings.ToLower(noteSortTitle(a)) < strings.ToLower(noteSortTitle(b))
+		case SortWords:
+			return a.WordCount < b.WordCount
+		default:
+			return a.LastUpdated().Before(b.LastUpdated())
+		}
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		if descending {
+			return less(notes[j], notes[i])
+		}
+		return less(notes[i], notes[j])
+	})
+}
+
+func noteSortTitle(n NoteInfo) string {
+	if strings.TrimSpace(n.Title) != "" {
+		return n.Title
+	}
+	return n.Filename
+}
@@ -0,0 +1,195 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+)
+
+const (
+	DayLayout   = "2006-01-02"
+	weeksShown  = 12
+	monthsShown = 12
+	topTagCount = 10
+)
+
+type Period struct {
+	Start   time.Time `json:"start"`
+	Entries int       `json:"entries"`
+	Words   int       `json:"words"`
+}
+
+type Day struct {
+	Date    string `json:"date"`
+	Entries int    `json:"entries"`
+	Words   int    `json:"words"`
+}
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type Summary struct {
+	TotalEntries  int        `json:"total_entries"`
+	TotalWords    int        `json:"total_words"`
+	AverageWords  float64    `json:"average_words"`
+	CurrentStreak int        `json:"current_streak"`
+	LongestStreak int        `json:"longest_streak"`
+	Weekly        []Period   `json:"weekly"`
+	Monthly       []Period   `json:"monthly"`
+	Activity      []Day      `json:"activity"`
+	TopTags       []TagCount `json:"top_tags"`
+}
+
+func Compute(notes []journal.NoteInfo, now time.Time) Summary {
+	now = now.Local()
+	summary := Summary{}
+	days := map[string]*Day{}
+	tags := map[string]int{}
+	countedWords := 0
+
+	for _, note := range notes {
+		summary.TotalEntries++
+		words := note.WordCount
+		if words >= 0 {
+			summary.TotalWords += words
+			countedWords++
+		} else {
+			words = 0
+		}
+		for _, tag := range note.Tags {
+			tags[tag]++
+		}
+
+		date, ok := note.Date()
+		if !ok {
+			continue
+		}
+		key := date.Local().Format(DayLayout)
+		day, ok := days[key]
+		if !ok {
+			day = &Day{Date: key}
+			days[key] = day
+		}
+		day.Entries++
+		day.Words += words
+	}
+
+	if countedWords > 0 {
+		summary.AverageWords = float64(summary.TotalWords) / float64(countedWords)
+	}
+
+	summary.Activity = make([]Day, 0, len(days))
+	for _, day := range days {
+		summary.Activity = append(summary.Activity, *day)
+	}
+	sort.Slice(summary.Activity, func(i, j int) bool {
+		return summary.Activity[i].Date < summary.Activity[j].Date
+	})
+
+	summary.CurrentStreak, summary.LongestStreak = streaks(summary.Activity, now)
+	summary.Weekly = buckets(summary.Activity, weekStart(now), weeksShown, func(t time.Time, n int) time.Time {
+		return t.AddDate(0, 0, 7*n)
+	})
+	summary.Monthly = buckets(summary.Activity, monthStart(now), monthsShown, func(t time.Time, n int) time.Time {
+		return t.AddDate(0, n, 0)
+	})
+	summary.TopTags = topTags(tags, topTagCount)
+	return summary
+}
+
+// ActivityByDay indexes the activity list by date for heatmap rendering.
+func (s Summary) ActivityByDay() map[string]int {
+	out := make(map[string]int, len(s.Activity))
+	for _, day := range s.Activity {
+		out[day.Date] = day.Entries
+	}
+	return out
+}
+
+func streaks(activity []Day, now time.Time) (int, int) {
+	if len(activity) == 0 {
+		return 0, 0
+	}
+	longest := 0
+	run := 0
+	var previous time.Time
+	for _, day := range activity {
+		date, err := time.ParseInLocation(DayLayout, day.Date, time.Local)
+		if err != nil {
+			continue
+		}
+		if !previous.IsZero() && previous.AddDate(0, 0, 1).Equal(date) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+		previous = date
+	}
+
+	// The current streak survives until the end of the day after the last
+	// entry, so an unwritten today does not reset it.
+	today := startOfDay(now)
+	if previous.Equal(today) || previous.Equal(today.AddDate(0, 0, -1)) {
+		return run, longest
+	}
+	return 0, longest
+}
+
+func buckets(activity []Day, current time.Time, count int, step func(time.Time, int) time.Time) []Period {
+	periods := make([]Period, count)
+	first := step(current, -(count - 1))
+	for i := range periods {
+		periods[i].Start = step(first, i)
+	}
+	for _, day := range activity {
+		date, err := time.ParseInLocation(DayLayout, day.Date, time.Local)
+		if err != nil || date.Before(first) {
+			continue
+		}
+		for i := len(periods) - 1; i >= 0; i-- {
+			if !date.Before(periods[i].Start) {
+				periods[i].Entries += day.Entries
+				periods[i].Words += day.Words
+				break
+			}
+		}
+	}
+	return periods
+}
+
+func topTags(counts map[string]int, limit int) []TagCount {
+	out := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		out = append(out, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+func weekStart(t time.Time) time.Time {
+	day := startOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 9, 0, 0, 0, time.Local)
+}
+
+func TestComputeStreaksAndTotals(t *testing.T) {
+	now := day(2024, 3, 10)
+	notes := []journal.NoteInfo{
+		{Created: day(2024, 3, 1), WordCount: 100, Tags: []string{"work"}},
+		{Created: day(2024, 3, 2), WordCount: 50, Tags: []string{"work", "family"}},
+		{Created: day(2024, 3, 3), WordCount: 30},
+		{Created: day(2024, 3, 8), WordCount: 20},
+		{Created: day(2024, 3, 9), WordCount: 10, Tags: []string{"work"}},
+		{Created: day(2024, 3, 9), WordCount: -1},
+		{Filename: "2024-03-05_10-00_Legacy.md", WordCount: 40},
+	}
+
+	summary := Compute(notes, now)
+	if summary.TotalEntries != 7 {
+		t.Fatalf("TotalEntries = %d, want 7", summary.TotalEntries)
+	}
+	if summary.TotalWords != 250 {
+		t.Fatalf("TotalWords = %d, want 250", summary.TotalWords)
+	}
+	if summary.AverageWords != 250.0/6 {
+		t.Fatalf("AverageWords = %v, want %v", summary.AverageWords, 250.0/6)
+	}
+	if summary.CurrentStreak != 2 {
+		t.Fatalf("CurrentStreak = %d, want 2", summary.CurrentStreak)
+	}
+	if summary.LongestStreak != 3 {
+		t.Fatalf("LongestStreak = %d, want 3", summary.LongestStreak)
+	}
+	if len(summary.TopTags) != 2 || summary.TopTags[0] != (TagCount{Tag: "work", Count: 3}) {
+		t.Fatalf("TopTags = %+v, want work first", summary.TopTags)
+	}
+	if len(summary.Activity) != 6 {
+		t.Fatalf("Activity days = %d, want 6", len(summary.Activity))
+	}
+
+	last := summary.Monthly[len(summary.Monthly)-1]
+	if last.Entries != 7 || last.Words != 250 {
+		t.Fatalf("current month = %+v, want 7 entries and 250 words", last)
+	}
+}
+
+func TestCurrentStreakResetsAfterMissedDay(t *testing.T) {
+	notes := []journal.NoteInfo{
+		{Created: day(2024, 3, 1), WordCount: 1},
+		{Created: day(2024, 3, 2), WordCount: 1},
+	}
+	summary := Compute(notes, day(2024, 3, 5))
+	if summary.CurrentStreak != 0 {
+		t.Fatalf("CurrentStreak = %d, want 0", summary.CurrentStreak)
+	}
+	if summary.LongestStreak != 2 {
+		t.Fatalf("LongestStreak = %d, want 2", summary.LongestStreak)
+	}
+}
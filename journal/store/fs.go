@@ -2,6 +2,7 @@ package store
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -9,11 +10,19 @@ import (
 	"time"
 )
 
+const BackupDir = ".backup"
+
 type NoteInfo struct {
 	Filename string
 	ModTime  time.Time
 }
 
+type FileInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
 type FS struct {
 	Root string
 }
@@ -53,6 +62,16 @@ func (s *FS) ListMarkdown() ([]NoteInfo, error) {
 	return notes, nil
 }
 
+// Stat returns a single note's listing entry. A missing note is reported
+// with an error wrapping fs.ErrNotExist.
+func (s *FS) Stat(filename string) (NoteInfo, error) {
+	info, err := os.Stat(filepath.Join(s.Root, filename))
+	if err != nil {
+		return NoteInfo{}, fmt.Errorf("stat note: %w", err)
+	}
+	return NoteInfo{Filename: filename, ModTime: info.ModTime()}, nil
+}
+
 func (s *FS) Read(filename string) (string, time.Time, error) {
 	path := filepath.Join(s.Root, filename)
 	content, err := os.ReadFile(path)
@@ -72,9 +91,167 @@ func (s *FS) Write(filename, content string) error {
 	if err := os.MkdirAll(s.Root, 0755); err != nil {
 		return fmt.Errorf("create journal dir: %w", err)
 	}
-	path := filepath.Join(s.Root, filename)
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	err := s.Replace(filename, func(w io.Writer) error {
+		_, err := io.WriteString(w, content)
+		return err
+	})
+	if err != nil {
 		return fmt.Errorf("save note: %w", err)
 	}
 	return nil
 }
+
+// Replace writes a file under the root to a temporary file in the same
+// folder and renames it into place, so a failed write leaves the old
+// content intact.
+func (s *FS) Replace(rel string, write func(io.Writer) error) error {
+	path := filepath.Join(s.Root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	err = write(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Backup copies content into a named set under the journal's .backup
+// folder and returns the set's directory.
+func (s *FS) Backup(set, filename, content string) (string, error) {
+	dir := filepath.Join(s.Root, BackupDir, set)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create backup dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, filename), []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("backup note: %w", err)
+	}
+	return dir, nil
+}
+
+// BackupFile copies a file under the root into a named set under the
+// journal's .backup folder, keeping its relative path, and returns the set's
+// directory.
+func (s *FS) BackupFile(set, rel string) (string, error) {
+	dir := filepath.Join(s.Root, BackupDir, set)
+	dst := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", fmt.Errorf("create backup dir: %w", err)
+	}
+	in, err := os.Open(filepath.Join(s.Root, filepath.FromSlash(rel)))
+	if err != nil {
+		return "", fmt.Errorf("backup file: %w", err)
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return "", fmt.Errorf("backup file: %w", err)
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("backup file: %w", err)
+	}
+	return dir, nil
+}
+
+func (s *FS) SetModTime(filename string, modTime time.Time) error {
+	path := filepath.Join(s.Root, filename)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		return fmt.Errorf("set note time: %w", err)
+	}
+	return nil
+}
+
+func (s *FS) Exists(rel string) bool {
+	_, err := os.Stat(filepath.Join(s.Root, filepath.FromSlash(rel)))
+	return err == nil
+}
+
+func (s *FS) CreateFile(rel string) (io.WriteCloser, error) {
+	path := filepath.Join(s.Root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create dir: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create file: %w", err)
+	}
+	return file, nil
+}
+
+func (s *FS) OpenFile(rel string) (io.ReadCloser, error) {
+	file, err := os.Open(filepath.Join(s.Root, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	return file, nil
+}
+
+func (s *FS) ListFiles(rel string) ([]FileInfo, error) {
+	entries, err := os.ReadDir(filepath.Join(s.Root, filepath.FromSlash(rel)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+	files := make([]FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("stat file: %w", err)
+		}
+		files = append(files, FileInfo{Name: entry.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files, nil
+}
+
+// Remove deletes a file under the root; a missing file is not an error.
+func (s *FS) Remove(rel string) error {
+	err := os.Remove(filepath.Join(s.Root, filepath.FromSlash(rel)))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove file: %w", err)
+	}
+	return nil
+}
+
+// MoveToBackup moves a file or folder under the root into a named set under
+// the journal's .backup folder, keeping its relative path. A missing file is
+// not an error.
+func (s *FS) MoveToBackup(set, rel string) error {
+	src := filepath.Join(s.Root, filepath.FromSlash(rel))
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+	dst := filepath.Join(s.Root, BackupDir, set, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("create backup dir: %w", err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("move to backup: %w", err)
+	}
+	return nil
+}
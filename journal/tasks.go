@@ -0,0 +1,63 @@
+package journal
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/never00rei/a7/journal/codec"
+)
+
+type Task struct {
+	Note NoteInfo
+	Line int
+	Text string
+	Done bool
+}
+
+// ListTasks collects checklist items from every note, newest note first and
+// in body order within a note. Notes that cannot be decrypted are skipped.
+func (s *Service) ListTasks() ([]Task, error) {
+	notes, err := s.ListNotes()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		a, _ := notes[i].Date()
+		b, _ := notes[j].Date()
+		return a.After(b)
+	})
+
+	var tasks []Task
+	for _, info := range notes {
+		note, err := s.LoadNote(info.Filename)
+		if err != nil {
+			continue
+		}
+		for _, task := range codec.ParseTasks(note.Content) {
+			tasks = append(tasks, Task{Note: info, Line: task.Line, Text: task.Text, Done: task.Done})
+		}
+	}
+	return tasks, nil
+}
+
+// ToggleTask checks or unchecks a task in its note. The task must still be
+// on the same line with the same text, so a note edited in the meantime is
+// never changed in the wrong place.
+func (s *Service) ToggleTask(task Task) error {
+	note, err := s.LoadNote(task.Note.Filename)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, current := range codec.ParseTasks(note.Content) {
+		if current.Line == task.Line && current.Text == task.Text {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("task %q changed since it was listed", task.Text)
+	}
+	body, _ := codec.ToggleTask(note.Content, task.Line)
+	return s.UpdateNote(task.Note.Filename, note.Title, body, note.Created)
+}
@@ -0,0 +1,145 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/never00rei/a7/journal/codec"
+	"github.com/never00rei/a7/utils"
+)
+
+const fileExt = ".md"
+
+// Template is a Markdown skeleton used to seed a new note. Title and Body
+// may contain variables such as {{date}} that are filled in by Render.
+type Template struct {
+	Name  string
+	Title string
+	Body  string
+}
+
+type Vars struct {
+	Now     time.Time
+	Journal string
+	Prompt  string
+}
+
+var builtins = []Template{
+	{
+		Name:  "daily-review",
+		Title: "Daily review {{date}}",
+		Body: "# {{weekday}}, {{date}}\n\n" +
+			"## What went well\n\n- \n\n" +
+			"## What was hard\n\n- \n\n" +
+			"## Tomorrow\n\n- \n",
+	},
+	{
+		Name:  "retro",
+		Title: "Retro {{date}}",
+		Body: "# Retro for {{journal}}\n\n" +
+			"## Keep doing\n\n- \n\n" +
+			"## Stop doing\n\n- \n\n" +
+			"## Try next\n\n- \n",
+	},
+	{
+		Name:  "gratitude",
+		Title: "Gratitude {{date}}",
+		Body: "# Three good things\n\n" +
+			"1. \n2. \n3. \n\n" +
+			"> {{prompt}}\n\n",
+	},
+}
+
+func Seed(dir string) error {
+	exists, err := utils.PathExists(dir)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	if err := utils.CreatePath(dir); err != nil {
+		return fmt.Errorf("create templates dir: %w", err)
+	}
+	for _, tmpl := range builtins {
+		if err := os.WriteFile(filepath.Join(dir, tmpl.Name+fileExt), []byte(tmpl.Content()), 0644); err != nil {
+			return fmt.Errorf("write template: %w", err)
+		}
+	}
+	return nil
+}
+
+// Load returns the templates in dir, which Seed fills with the built-in
+// ones the first time, so deleting a built-in's file removes it. Without a
+// dir it returns the built-ins.
+func Load(dir string) ([]Template, error) {
+	if dir == "" {
+		return append([]Template(nil), builtins...), nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("list templates: %w", err)
+	}
+	var out []Template
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExt) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("load template: %w", err)
+		}
+		out = append(out, Parse(strings.TrimSuffix(entry.Name(), fileExt), string(content)))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+func Find(list []Template, name string) (Template, bool) {
+	for _, tmpl := range list {
+		if strings.EqualFold(tmpl.Name, name) {
+			return tmpl, true
+		}
+	}
+	return Template{}, false
+}
+
+func Parse(name, content string) Template {
+	matter, body := codec.ParseFrontMatter(content)
+	return Template{
+		Name:  name,
+		Title: matter.Title,
+		Body:  body,
+	}
+}
+
+func (t Template) Content() string {
+	if t.Title == "" {
+		return t.Body
+	}
+	return fmt.Sprintf("---\ntitle: %s\n---\n\n%s", t.Title, t.Body)
+}
+
+func (t Template) Render(vars Vars) (string, string) {
+	if vars.Now.IsZero() {
+		vars.Now = time.Now()
+	}
+	replacer := strings.NewReplacer(
+		"{{date}}", vars.Now.Format("2006-01-02"),
+		"{{time}}", vars.Now.Format("15:04"),
+		"{{weekday}}", vars.Now.Weekday().String(),
+		"{{journal}}", vars.Journal,
+		"{{prompt}}", vars.Prompt,
+	)
+	return replacer.Replace(t.Title), replacer.Replace(t.Body)
+}
+
+func (t Template) UsesPrompt() bool {
+	return strings.Contains(t.Title, "{{prompt}}") || strings.Contains(t.Body, "{{prompt}}")
+}
@@ -0,0 +1,83 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRenderSubstitutesVariables(t *testing.T) {
+	tmpl := Template{
+		Name:  "test",
+		Title: "Review {{date}}",
+		Body:  "{{weekday}} in {{journal}} at {{time}}: {{prompt}}",
+	}
+	now := time.Date(2024, 5, 6, 7, 8, 0, 0, time.UTC)
+
+	title, body := tmpl.Render(Vars{Now: now, Journal: "work", Prompt: "Why?"})
+	if title != "Review 2024-05-06" {
+		t.Fatalf("title = %q, want %q", title, "Review 2024-05-06")
+	}
+	if want := "Monday in work at 07:08: Why?"; body != want {
+		t.Fatalf("body = %q, want %q", body, want)
+	}
+}
+
+func TestLoadReadsSeededTemplatesFromDisk(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "templates")
+	if err := Seed(dir); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+	if err := os.Remove(filepath.Join(dir, "gratitude.md")); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	content := "---\ntitle: My retro {{date}}\n---\n\nCustom body\n"
+	if err := os.WriteFile(filepath.Join(dir, "retro.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "standup.md"), []byte("Yesterday / Today\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	retro, ok := Find(loaded, "RETRO")
+	if !ok {
+		t.Fatalf("retro template missing")
+	}
+	if retro.Title != "My retro {{date}}" || retro.Body != "Custom body\n" {
+		t.Fatalf("retro = %+v, want user override", retro)
+	}
+	if _, ok := Find(loaded, "standup"); !ok {
+		t.Fatalf("standup template missing")
+	}
+	if _, ok := Find(loaded, "daily-review"); !ok {
+		t.Fatalf("seeded daily-review template missing")
+	}
+	if _, ok := Find(loaded, "gratitude"); ok {
+		t.Fatalf("deleted gratitude template came back")
+	}
+}
+
+func TestSeedWritesBuiltinsOnce(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "templates")
+	if err := Seed(dir); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+	path := filepath.Join(dir, "daily-review.md")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("seeded template missing: %v", err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := Seed(dir); err != nil {
+		t.Fatalf("Seed again: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Seed rewrote templates in an existing dir")
+	}
+}
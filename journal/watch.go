@@ -0,0 +1,117 @@
+package journal
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Change is a note file that was created, written or removed in the
+// journal folder, by a7 or anything else.
+type Change struct {
+	Filename string
+	Removed  bool
+}
+
+// Watcher reports note changes in batches. Events are collected until the
+// folder has been quiet for the debounce interval, so an editor's write and
+// rename or a sync tool's burst of files arrive as one batch.
+type Watcher struct {
+	Root    string
+	Changes <-chan []Change
+	Errors  <-chan error
+
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Watch starts watching the journal folder for note changes. Hidden files
+// and folders, such as drafts and backups, are ignored.
+func (s *Service) Watch(debounce time.Duration) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fsw.Add(s.Root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	changes := make(chan []Change)
+	errs := make(chan error)
+	w := &Watcher{
+		Root:    s.Root,
+		Changes: changes,
+		Errors:  errs,
+		watcher: fsw,
+		done:    make(chan struct{}),
+	}
+	go w.run(s, debounce, changes, errs)
+	return w, nil
+}
+
+func (w *Watcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.watcher.Close()
+	})
+	return err
+}
+
+func (w *Watcher) run(s *Service, debounce time.Duration, changes chan<- []Change, errs chan<- error) {
+	defer close(changes)
+	defer close(errs)
+	pending := make(map[string]bool)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-w.done:
+			timer.Stop()
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			name := filepath.Base(event.Name)
+			if !isNoteFile(name) || event.Op == fsnotify.Chmod {
+				continue
+			}
+			pending[name] = true
+			timer.Reset(debounce)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			select {
+			case errs <- err:
+			case <-w.done:
+				return
+			}
+		case <-timer.C:
+			batch := make([]Change, 0, len(pending))
+			for name := range pending {
+				_, err := s.store.Stat(name)
+				batch = append(batch, Change{Filename: name, Removed: errors.Is(err, fs.ErrNotExist)})
+			}
+			sort.Slice(batch, func(i, j int) bool { return batch[i].Filename < batch[j].Filename })
+			pending = make(map[string]bool)
+			select {
+			case changes <- batch:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+func isNoteFile(name string) bool {
+	return strings.HasSuffix(name, ".md") && !strings.HasPrefix(name, ".")
+}
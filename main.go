@@ -2,13 +2,13 @@ package main
 
 import (
 	"log"
+	"os"
 
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/never00rei/a7/ui/app"
+	"github.com/never00rei/a7/cli"
 )
 
 func main() {
-	if _, err := tea.NewProgram(app.NewAppModel(), tea.WithAltScreen()).Run(); err != nil {
+	if err := cli.Run(os.Args[1:], os.Stdout); err != nil {
 		log.Fatal(err)
 	}
 }
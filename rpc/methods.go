@@ -0,0 +1,203 @@
+package rpc
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+)
+
+type idParams struct {
+	ID string `json:"id"`
+}
+
+type saveParams struct {
+	Title   string     `json:"title"`
+	Body    string     `json:"body"`
+	Tags    []string   `json:"tags"`
+	Prompt  string     `json:"prompt"`
+	Created *time.Time `json:"created"`
+}
+
+// updateParams leave out what should not change: a missing title, tags or
+// created time keeps the note's current one.
+type updateParams struct {
+	ID      string     `json:"id"`
+	Title   *string    `json:"title"`
+	Body    *string    `json:"body"`
+	Tags    []string   `json:"tags"`
+	Created *time.Time `json:"created"`
+}
+
+type searchParams struct {
+	Query string `json:"query"`
+}
+
+func (s *Server) version(json.RawMessage) (any, error) {
+	methods := make([]string, 0, len(s.methods))
+	for name := range s.methods {
+		methods = append(methods, name)
+	}
+	sort.Strings(methods)
+	return Version{Schema: SchemaVersion, Methods: methods}, nil
+}
+
+// list returns every note's metadata, newest first.
+func (s *Server) list(json.RawMessage) (any, error) {
+	infos, err := s.svc.ListNotes()
+	if err != nil {
+		return nil, err
+	}
+	journal.SortNotes(infos, journal.SortCreated, true)
+	notes := make([]Note, 0, len(infos))
+	for _, info := range infos {
+		notes = append(notes, s.noteFromInfo(info))
+	}
+	return notes, nil
+}
+
+func (s *Server) load(raw json.RawMessage) (any, error) {
+	var params idParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	name, err := filename(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadNote(name)
+}
+
+func (s *Server) save(raw json.RawMessage) (any, error) {
+	var params saveParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(params.Title) == "" {
+		return nil, newError(CodeInvalidParams, KindInvalidParams, "title is required")
+	}
+	created := s.now()
+	if params.Created != nil {
+		created = *params.Created
+	}
+	opts := []journal.NoteOption{journal.WithTags(params.Tags...)}
+	if params.Prompt != "" {
+		opts = append(opts, journal.WithPrompt(params.Prompt))
+	}
+	name, body, err := s.svc.SaveEdit(journal.Edit{Title: params.Title, Body: params.Body, Created: created, Options: opts})
+	if err != nil {
+		return nil, err
+	}
+	return s.savedNote(name, body)
+}
+
+func (s *Server) update(raw json.RawMessage) (any, error) {
+	var params updateParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	name, err := filename(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if params.Body == nil {
+		return nil, newError(CodeInvalidParams, KindInvalidParams, "body is required")
+	}
+	info, err := s.svc.StatNote(name)
+	if err != nil {
+		return nil, err
+	}
+	title := info.Title
+	if params.Title != nil {
+		title = *params.Title
+	}
+	created, _ := info.Date()
+	if params.Created != nil {
+		created = *params.Created
+	}
+	var opts []journal.NoteOption
+	if params.Tags != nil {
+		opts = append(opts, journal.ReplaceTags(params.Tags...))
+	}
+	edit := journal.Edit{Filename: name, Title: title, Body: *params.Body, Created: created, Options: opts, Rename: title != info.Title}
+	_, body, err := s.svc.SaveEdit(edit)
+	if err != nil {
+		return nil, err
+	}
+	return s.savedNote(name, body)
+}
+
+// delete moves a note to the journal's backup folder and returns that
+// folder's path.
+func (s *Server) delete(raw json.RawMessage) (any, error) {
+	var params idParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	name, err := filename(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	backup, err := s.svc.DeleteNote(name)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"backup": backup}, nil
+}
+
+// savedNote reports a note just written with body, as left by the pre-save
+// hooks, without decrypting it again.
+func (s *Server) savedNote(name, body string) (Note, error) {
+	info, err := s.svc.StatNote(name)
+	if err != nil {
+		return Note{}, err
+	}
+	note := s.noteFromInfo(info)
+	note.Content = &body
+	return note, nil
+}
+
+func (s *Server) search(raw json.RawMessage) (any, error) {
+	var params searchParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(params.Query) == "" {
+		return nil, newError(CodeInvalidParams, KindInvalidParams, "query is required")
+	}
+	results, err := s.svc.Search(params.Query)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SearchResult, 0, len(results))
+	for _, result := range results {
+		note := s.noteFromInfo(result.Note)
+		note.Locked = result.Locked
+		out = append(out, SearchResult{Note: note, Line: result.Line, Snippet: result.Snippet})
+	}
+	return out, nil
+}
+
+// today returns the notes written today, newest first, so a plugin can open
+// the day's entry or start one with save.
+func (s *Server) today(json.RawMessage) (any, error) {
+	infos, err := s.svc.ListNotes()
+	if err != nil {
+		return nil, err
+	}
+	journal.SortNotes(infos, journal.SortCreated, true)
+	y, m, d := s.now().Date()
+	notes := []Note{}
+	for _, info := range infos {
+		date, ok := info.Date()
+		if !ok {
+			continue
+		}
+		if dy, dm, dd := date.Local().Date(); dy == y && dm == m && dd == d {
+			notes = append(notes, s.noteFromInfo(info))
+		}
+	}
+	return notes, nil
+}
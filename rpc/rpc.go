@@ -0,0 +1,265 @@
+// Package rpc exposes a journal to editor plugins as JSON-RPC 2.0 over a
+// pair of streams, one message per line. The methods and their JSON shapes
+// are versioned by SchemaVersion, which the version method reports.
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/hooks"
+	"github.com/never00rei/a7/journal/lock"
+)
+
+// SchemaVersion changes whenever a method or its params or results change
+// in a way existing clients would notice.
+const SchemaVersion = 1
+
+// JSON-RPC 2.0 error codes, followed by the a7 specific ones.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternal       = -32603
+
+	CodeNotFound   = 1
+	CodeDecrypt    = 2
+	CodeLocked     = 3
+	CodeRejected   = 4
+	CodeHookFailed = 5
+)
+
+// Error kinds let clients tell failures apart without matching messages.
+const (
+	KindParse          = "parse_error"
+	KindInvalidRequest = "invalid_request"
+	KindMethodNotFound = "method_not_found"
+	KindInvalidParams  = "invalid_params"
+	KindInternal       = "internal"
+	KindNotFound       = "not_found"
+	KindDecrypt        = "decrypt_failed"
+	KindLocked         = "locked"
+	KindRejected       = "rejected"
+	KindHookFailed     = "hook_failed"
+)
+
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type Error struct {
+	Code    int        `json:"code"`
+	Message string     `json:"message"`
+	Data    *ErrorData `json:"data,omitempty"`
+}
+
+type ErrorData struct {
+	Kind string `json:"kind"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Note is a note's metadata and, from load, save and update, its body.
+// Locked notes are encrypted notes the configured key cannot open.
+type Note struct {
+	ID        string    `json:"id"`
+	Filename  string    `json:"filename"`
+	Path      string    `json:"path"`
+	Title     string    `json:"title"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+	Encrypted bool      `json:"encrypted"`
+	Locked    bool      `json:"locked,omitempty"`
+	WordCount int       `json:"word_count"`
+	Prompt    string    `json:"prompt,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Content   *string   `json:"content,omitempty"`
+}
+
+type SearchResult struct {
+	Note    Note   `json:"note"`
+	Line    int    `json:"line,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+type Version struct {
+	Schema  int      `json:"schema"`
+	Methods []string `json:"methods"`
+}
+
+type Server struct {
+	svc     *journal.Service
+	now     func() time.Time
+	methods map[string]func(json.RawMessage) (any, error)
+}
+
+func New(svc *journal.Service) *Server {
+	s := &Server{svc: svc, now: time.Now}
+	s.methods = map[string]func(json.RawMessage) (any, error){
+		"version": s.version,
+		"list":    s.list,
+		"load":    s.load,
+		"save":    s.save,
+		"update":  s.update,
+		"delete":  s.delete,
+		"search":  s.search,
+		"today":   s.today,
+	}
+	return s
+}
+
+// Serve answers requests read from r, one per line, until r is exhausted.
+// Notifications, requests without an id, get no response.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	reader := bufio.NewReader(r)
+	encoder := json.NewEncoder(w)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			if resp, ok := s.handleLine(line); ok {
+				if err := encoder.Encode(resp); err != nil {
+					return err
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handleLine(line []byte) (Response, bool) {
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return errorResponse(nil, newError(CodeParseError, KindParse, "parse error: %v", err)), true
+	}
+	resp := s.Handle(req)
+	return resp, len(req.ID) > 0
+}
+
+// Handle runs a single request.
+func (s *Server) Handle(req Request) Response {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, newError(CodeInvalidRequest, KindInvalidRequest, "invalid request"))
+	}
+	method, ok := s.methods[req.Method]
+	if !ok {
+		return errorResponse(req.ID, newError(CodeMethodNotFound, KindMethodNotFound, "unknown method %q", req.Method))
+	}
+	result, err := method(req.Params)
+	if err != nil {
+		return errorResponse(req.ID, toError(err))
+	}
+	return Response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func errorResponse(id json.RawMessage, err *Error) Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return Response{JSONRPC: "2.0", ID: id, Error: err}
+}
+
+func newError(code int, kind, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), Data: &ErrorData{Kind: kind}}
+}
+
+func toError(err error) *Error {
+	var rpcErr *Error
+	var held *lock.HeldError
+	var hookErr *hooks.Error
+	switch {
+	case errors.As(err, &rpcErr):
+		return rpcErr
+	case errors.Is(err, journal.ErrNotFound):
+		return newError(CodeNotFound, KindNotFound, "%v", err)
+	case errors.Is(err, journal.ErrDecrypt):
+		return newError(CodeDecrypt, KindDecrypt, "%v", err)
+	case errors.As(err, &held):
+		return newError(CodeLocked, KindLocked, "%v", err)
+	case errors.Is(err, hooks.ErrRejected):
+		return newError(CodeRejected, KindRejected, "%v", err)
+	case errors.As(err, &hookErr):
+		return newError(CodeHookFailed, KindHookFailed, "%v", err)
+	default:
+		return newError(CodeInternal, KindInternal, "%v", err)
+	}
+}
+
+func decodeParams(raw json.RawMessage, params any) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, params); err != nil {
+		return newError(CodeInvalidParams, KindInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
+
+// filename turns a note ID or filename into a filename in the journal
+// folder, rejecting anything that would reach outside it.
+func filename(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" || filepath.Base(id) != id || strings.HasPrefix(id, ".") {
+		return "", newError(CodeInvalidParams, KindInvalidParams, "invalid note id %q", id)
+	}
+	if !strings.HasSuffix(id, ".md") {
+		id += ".md"
+	}
+	return id, nil
+}
+
+func (s *Server) noteFromInfo(info journal.NoteInfo) Note {
+	note := Note{
+		ID:        journal.NoteID(info.Filename),
+		Filename:  info.Filename,
+		Path:      filepath.Join(s.svc.Root, info.Filename),
+		Title:     info.Title,
+		Updated:   info.LastUpdated(),
+		Encrypted: info.Encrypted,
+		WordCount: info.WordCount,
+		Prompt:    info.Prompt,
+		Tags:      info.Tags,
+	}
+	note.Created, _ = info.Date()
+	return note
+}
+
+// loadNote returns a note with its body. Notes the key cannot decrypt are
+// an error, since callers asked for the body.
+func (s *Server) loadNote(filename string) (Note, error) {
+	info, err := s.svc.StatNote(filename)
+	if err != nil {
+		return Note{}, err
+	}
+	loaded, err := s.svc.OpenNote(filename)
+	if err != nil {
+		return Note{}, err
+	}
+	note := s.noteFromInfo(info)
+	note.Content = &loaded.Content
+	return note, nil
+}
@@ -0,0 +1,179 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/codec"
+	"github.com/never00rei/a7/journal/hooks"
+)
+
+func TestServeAnswersRequestsWithStructuredErrors(t *testing.T) {
+	root := t.TempDir()
+	svc := journal.NewService(root)
+	now := time.Now()
+	secret := codec.RenderContent(codec.FrontMatter{Title: "Secret", Created: now.Add(-time.Minute), Encrypted: true},
+		"-----BEGIN AGE ENCRYPTED FILE-----\nnot really\n-----END AGE ENCRYPTED FILE-----\n")
+	secretFile := codec.BuildFilename("Secret", now.Add(-time.Minute))
+	if err := os.WriteFile(filepath.Join(root, secretFile), []byte(secret), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := svc.SaveNote("Last year", "old basil", now.AddDate(-1, 0, 0)); err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"version"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"save","params":{"title":"Plugin","body":"Written from the editor #vim","tags":["draft"]}}`,
+		`{"jsonrpc":"2.0","method":"list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"load","params":{"id":"missing"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"load","params":{"id":"` + journal.NoteID(secretFile) + `"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"load","params":{"id":"../conf.ini"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"nope"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":7,"method":"today"}`,
+		`{"jsonrpc":"2.0","id":8,"method":"search","params":{"query":"basil"}}`,
+	}, "\n")
+	var out bytes.Buffer
+	if err := New(svc).Serve(strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	type response struct {
+		ID     json.RawMessage `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *Error          `json:"error"`
+	}
+	var responses []response
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var resp response
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	if len(responses) != 9 {
+		t.Fatalf("got %d responses, want 9 (no reply to the notification)", len(responses))
+	}
+
+	var version Version
+	json.Unmarshal(responses[0].Result, &version)
+	if version.Schema != SchemaVersion || len(version.Methods) != 8 {
+		t.Fatalf("version = %+v", version)
+	}
+
+	var saved Note
+	json.Unmarshal(responses[1].Result, &saved)
+	if saved.Title != "Plugin" || saved.Content == nil || strings.Join(saved.Tags, ",") != "draft,vim" {
+		t.Fatalf("saved = %+v", saved)
+	}
+	if _, err := os.Stat(saved.Path); err != nil {
+		t.Fatalf("saved path: %v", err)
+	}
+
+	wantErrors := map[int]string{2: KindNotFound, 3: KindDecrypt, 4: KindInvalidParams, 5: KindMethodNotFound, 6: KindParse}
+	for i, kind := range wantErrors {
+		if responses[i].Error == nil || responses[i].Error.Data.Kind != kind {
+			t.Fatalf("response %d error = %+v, want %s", i, responses[i].Error, kind)
+		}
+	}
+
+	var today []Note
+	json.Unmarshal(responses[7].Result, &today)
+	if len(today) != 2 || today[0].Title != "Plugin" || today[1].Title != "Secret" {
+		t.Fatalf("today = %+v", today)
+	}
+
+	var results []SearchResult
+	json.Unmarshal(responses[8].Result, &results)
+	if len(results) != 1 || results[0].Note.Title != "Last year" || results[0].Snippet != "old basil" {
+		t.Fatalf("search = %+v", results)
+	}
+}
+
+func TestUpdateKeepsOmittedFields(t *testing.T) {
+	svc := journal.NewService(t.TempDir())
+	created := time.Date(2024, 5, 1, 9, 30, 0, 0, time.Local)
+	name, err := svc.SaveNote("Morning", "first", created, journal.WithTags("walk"))
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+
+	resp := New(svc).Handle(Request{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage("1"),
+		Method:  "update",
+		Params:  json.RawMessage(`{"id":"` + journal.NoteID(name) + `","body":"second"}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("update: %v", resp.Error)
+	}
+	note, err := svc.LoadNote(name)
+	if err != nil {
+		t.Fatalf("LoadNote: %v", err)
+	}
+	if note.Title != "Morning" || note.Content != "second" || !note.Created.Equal(created) || strings.Join(note.Tags, ",") != "walk" {
+		t.Fatalf("note = %+v", note)
+	}
+}
+
+func TestSaveReturnsBodyFromPreSaveHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	hook := "#!/bin/sh\necho '{\"body\":\"tidied\"}'\n"
+	if err := os.WriteFile(filepath.Join(dir, "pre-save"), []byte(hook), 0755); err != nil {
+		t.Fatalf("write hook: %v", err)
+	}
+	svc := journal.NewService(t.TempDir(), journal.WithHooks(hooks.NewRunner(dir)))
+
+	resp := New(svc).Handle(Request{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage("1"),
+		Method:  "save",
+		Params:  json.RawMessage(`{"title":"Messy","body":"messy"}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("save: %v", resp.Error)
+	}
+	note, ok := resp.Result.(Note)
+	if !ok || note.Content == nil || *note.Content != "tidied" {
+		t.Fatalf("result = %+v", resp.Result)
+	}
+}
+
+func TestUpdateRenameRewritesLinks(t *testing.T) {
+	svc := journal.NewService(t.TempDir())
+	now := time.Now()
+	target, err := svc.SaveNote("Old Title", "linked to", now)
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	source, err := svc.SaveNote("Source", "see [[Old Title]]", now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+
+	resp := New(svc).Handle(Request{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage("1"),
+		Method:  "update",
+		Params:  json.RawMessage(`{"id":"` + journal.NoteID(target) + `","title":"New Title","body":"linked to"}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("update: %v", resp.Error)
+	}
+	note, err := svc.LoadNote(source)
+	if err != nil || note.Content != "see [[New Title]]" {
+		t.Fatalf("linking note = %+v, %v", note, err)
+	}
+}
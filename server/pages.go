@@ -0,0 +1,162 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+)
+
+type indexPage struct {
+	Title   string
+	Query   string
+	Notes   []Note
+	Results []SearchResult
+}
+
+type notePage struct {
+	Title string
+	Note  Note
+	Body  template.HTML
+}
+
+func render(w http.ResponseWriter, name string, page any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pages.ExecuteTemplate(w, name, page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func handleStylesheet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Write([]byte(stylesheet))
+}
+
+var pages = template.Must(template.New("pages").Funcs(template.FuncMap{
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Local().Format("Mon 2 Jan 2006")
+	},
+	"datetime": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Local().Format(time.RFC1123)
+	},
+}).Parse(`
+{{define "head"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="/style.css">
+</head>
+<body>
+<nav><a href="/">Journal</a></nav>
+<main>
+{{end}}
+{{define "foot"}}</main>
+</body>
+</html>
+{{end}}
+{{define "note-link"}}<a href="/notes/{{.ID}}">{{.Title}}</a> <span class="date">{{date .Created}}</span>{{if .Encrypted}} <span class="locked">encrypted</span>{{end}}{{end}}
+{{define "index"}}{{template "head" .}}<h1>{{.Title}}</h1>
+<form action="/" method="get"><input type="search" name="q" value="{{.Query}}" placeholder="Search entries" aria-label="Search entries"></form>
+{{if .Query}}<ul>{{range .Results}}
+<li>{{template "note-link" .Note}}{{if .Snippet}}<p class="snippet">{{.Snippet}}</p>{{end}}</li>{{else}}
+<li>No entries match.</li>{{end}}
+</ul>
+{{else}}<ul>{{range .Notes}}
+<li>{{template "note-link" .}}</li>{{else}}
+<li>No entries yet.</li>{{end}}
+</ul>
+{{end}}{{template "foot" .}}{{end}}
+{{define "note"}}{{template "head" .}}<article>
+<h1>{{.Title}}</h1>
+<dl class="meta">
+{{with datetime .Note.Created}}<dt>Created</dt><dd>{{.}}</dd>{{end}}
+{{with datetime .Note.Updated}}<dt>Updated</dt><dd>{{.}}</dd>{{end}}
+{{if ge .Note.WordCount 0}}<dt>Words</dt><dd>{{.Note.WordCount}}</dd>{{end}}
+<dt>Encrypted</dt><dd>{{if .Note.Encrypted}}Yes{{else}}No{{end}}</dd>
+{{if .Note.Prompt}}<dt>Prompt</dt><dd>{{.Note.Prompt}}</dd>{{end}}
+{{if .Note.Tags}}<dt>Tags</dt><dd>{{range .Note.Tags}}<a href="/?q={{.}}">#{{.}}</a> {{end}}</dd>{{end}}
+</dl>
+{{if .Note.Locked}}<p class="locked">This entry is encrypted and the server was started without its key.</p>
+{{else}}<div class="body">
+{{.Body}}
+</div>
+{{end}}</article>
+{{template "foot" .}}{{end}}
+`))
+
+const stylesheet = `body {
+  margin: 0;
+  font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif;
+  line-height: 1.6;
+  color: #1f2328;
+  background: #fafafa;
+}
+nav {
+  padding: 0.75rem 1.5rem;
+  border-bottom: 1px solid #d0d7de;
+  background: #fff;
+}
+main {
+  max-width: 46rem;
+  margin: 0 auto;
+  padding: 1.5rem;
+}
+a {
+  color: #0969da;
+  text-decoration: none;
+}
+a:hover {
+  text-decoration: underline;
+}
+ul {
+  padding-left: 1.2rem;
+}
+input[type=search] {
+  width: 100%;
+  box-sizing: border-box;
+  padding: 0.5rem 0.75rem;
+  font-size: 1rem;
+  border: 1px solid #d0d7de;
+  border-radius: 6px;
+}
+.date {
+  color: #656d76;
+  font-size: 0.9em;
+}
+.snippet {
+  margin: 0.1rem 0 0.6rem;
+  color: #656d76;
+}
+.locked {
+  color: #9a6700;
+}
+.meta {
+  display: grid;
+  grid-template-columns: max-content 1fr;
+  gap: 0.2rem 1rem;
+  color: #656d76;
+  font-size: 0.9em;
+}
+.meta dd {
+  margin: 0;
+}
+.body pre {
+  overflow-x: auto;
+  padding: 0.75rem;
+  background: #f0f2f4;
+}
+@media (prefers-color-scheme: dark) {
+  body { color: #e6edf3; background: #0d1117; }
+  nav { background: #161b22; border-color: #30363d; }
+  a { color: #4493f8; }
+  input[type=search] { color: inherit; background: #0d1117; border-color: #30363d; }
+  .body pre { background: #161b22; }
+}
+`
@@ -0,0 +1,285 @@
+// Package server serves a journal read-only over HTTP: a small web UI for
+// reading on another device and a JSON API with the same list, show and
+// search operations.
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+const tokenCookie = "a7_token"
+
+// Options configure a Server. Token is required on every request, as a
+// bearer token, a token query parameter or the cookie set after the first
+// visit. Secure marks that cookie for HTTPS only.
+type Options struct {
+	Title  string
+	Token  string
+	Secure bool
+}
+
+type Server struct {
+	svc  *journal.Service
+	opts Options
+	mux  *http.ServeMux
+}
+
+// Note is the JSON form of a note. Locked notes are encrypted notes the
+// server has no key for, so only their metadata is sent.
+type Note struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+	Encrypted bool      `json:"encrypted"`
+	Locked    bool      `json:"locked"`
+	WordCount int       `json:"word_count"`
+	Prompt    string    `json:"prompt,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Content   string    `json:"content,omitempty"`
+}
+
+type SearchResult struct {
+	Note    Note   `json:"note"`
+	Line    int    `json:"line,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// New serves svc. Encrypted notes are only readable when svc was created
+// with a key that decrypts them.
+func New(svc *journal.Service, opts Options) *Server {
+	if strings.TrimSpace(opts.Title) == "" {
+		opts.Title = "Journal"
+	}
+	s := &Server{svc: svc, opts: opts, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /{$}", s.handleIndex)
+	s.mux.HandleFunc("GET /notes/{id}", s.handleNote)
+	s.mux.HandleFunc("GET /style.css", handleStylesheet)
+	s.mux.HandleFunc("GET /api/notes", s.handleAPINotes)
+	s.mux.HandleFunc("GET /api/notes/{id}", s.handleAPINote)
+	s.mux.HandleFunc("GET /api/search", s.handleAPISearch)
+	return s
+}
+
+// NewToken returns a random access token for servers started without one.
+func NewToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	if token := r.URL.Query().Get("token"); token != "" && s.validToken(token) {
+		// Swap the token in the link for a cookie so it does not stay in the
+		// address bar or history.
+		http.SetCookie(w, &http.Cookie{
+			Name:     tokenCookie,
+			Value:    token,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   s.opts.Secure,
+			SameSite: http.SameSiteStrictMode,
+		})
+		query := r.URL.Query()
+		query.Del("token")
+		target := *r.URL
+		target.RawQuery = query.Encode()
+		http.Redirect(w, r, target.RequestURI(), http.StatusSeeOther)
+		return
+	}
+	if !s.authorized(r) {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		http.Error(w, "Open the link printed by a7 serve, which includes the access token.", http.StatusUnauthorized)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return s.validToken(auth)
+	}
+	if cookie, err := r.Cookie(tokenCookie); err == nil {
+		return s.validToken(cookie.Value)
+	}
+	return false
+}
+
+func (s *Server) validToken(token string) bool {
+	return s.opts.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	page := indexPage{Title: s.opts.Title, Query: query}
+	if query != "" {
+		results, err := s.svc.Search(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, result := range results {
+			page.Results = append(page.Results, searchResult(result))
+		}
+	} else {
+		notes, err := s.listNotes()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		page.Notes = notes
+	}
+	render(w, "index", page)
+}
+
+func (s *Server) handleNote(w http.ResponseWriter, r *http.Request) {
+	note, err := s.loadNote(r.PathValue("id"))
+	if errors.Is(err, fs.ErrNotExist) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	page := notePage{Title: note.Title, Note: note}
+	if !note.Locked {
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(note.Content), &buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		page.Body = template.HTML(buf.String())
+	}
+	render(w, "note", page)
+}
+
+func (s *Server) handleAPINotes(w http.ResponseWriter, r *http.Request) {
+	notes, err := s.listNotes()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, notes)
+}
+
+func (s *Server) handleAPINote(w http.ResponseWriter, r *http.Request) {
+	note, err := s.loadNote(r.PathValue("id"))
+	if errors.Is(err, fs.ErrNotExist) {
+		writeError(w, http.StatusNotFound, "note not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, note)
+}
+
+func (s *Server) handleAPISearch(w http.ResponseWriter, r *http.Request) {
+	results, err := s.svc.Search(r.URL.Query().Get("q"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]SearchResult, 0, len(results))
+	for _, result := range results {
+		out = append(out, searchResult(result))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// listNotes returns every note's metadata, newest first.
+func (s *Server) listNotes() ([]Note, error) {
+	infos, err := s.svc.ListNotes()
+	if err != nil {
+		return nil, err
+	}
+	journal.SortNotes(infos, journal.SortCreated, true)
+	notes := make([]Note, 0, len(infos))
+	for _, info := range infos {
+		notes = append(notes, noteFromInfo(info))
+	}
+	return notes, nil
+}
+
+// loadNote looks id up among the listed notes rather than building a path
+// from it, so requests can only reach notes in the journal.
+func (s *Server) loadNote(id string) (Note, error) {
+	infos, err := s.svc.ListNotes()
+	if err != nil {
+		return Note{}, err
+	}
+	for _, info := range infos {
+		if journal.NoteID(info.Filename) != id {
+			continue
+		}
+		note := noteFromInfo(info)
+		loaded, err := s.svc.LoadNote(info.Filename)
+		if loaded == nil {
+			return Note{}, err
+		}
+		note.Locked = err != nil
+		if !note.Locked {
+			note.Content = loaded.Content
+		}
+		return note, nil
+	}
+	return Note{}, fs.ErrNotExist
+}
+
+func noteFromInfo(info journal.NoteInfo) Note {
+	note := Note{
+		ID:        journal.NoteID(info.Filename),
+		Title:     info.Title,
+		Updated:   info.LastUpdated(),
+		Encrypted: info.Encrypted,
+		WordCount: info.WordCount,
+		Prompt:    info.Prompt,
+		Tags:      info.Tags,
+	}
+	note.Created, _ = info.Date()
+	if strings.TrimSpace(note.Title) == "" {
+		note.Title = note.ID
+	}
+	return note
+}
+
+func searchResult(result journal.SearchResult) SearchResult {
+	note := noteFromInfo(result.Note)
+	note.Locked = result.Locked
+	return SearchResult{Note: note, Line: result.Line, Snippet: result.Snippet}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
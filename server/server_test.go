@@ -0,0 +1,94 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/codec"
+)
+
+func TestServerRequiresTokenAndServesNotes(t *testing.T) {
+	root := t.TempDir()
+	svc := journal.NewService(root)
+	now := time.Now()
+	filename, err := svc.SaveNote("Garden", "Planted <b>basil</b>", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	secret := codec.RenderContent(codec.FrontMatter{Title: "Secret", Created: now, Encrypted: true, WordCount: 2},
+		"-----BEGIN AGE ENCRYPTED FILE-----\nnot really\n-----END AGE ENCRYPTED FILE-----\n")
+	if err := os.WriteFile(filepath.Join(root, codec.BuildFilename("Secret", now)), []byte(secret), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	srv := New(svc, Options{Token: "s3cret"})
+
+	get := func(path string, header, value string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("/api/notes", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no token: status %d", rec.Code)
+	}
+	if rec := get("/api/notes", "Authorization", "Bearer wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("wrong token: status %d", rec.Code)
+	}
+
+	rec := get("/?token=s3cret", "", "")
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/" {
+		t.Fatalf("token link: status %d location %q", rec.Code, rec.Header().Get("Location"))
+	}
+	cookie := rec.Result().Cookies()[0]
+	rec = get("/", "Cookie", cookie.Name+"="+cookie.Value)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Garden") || !strings.Contains(rec.Body.String(), "encrypted") {
+		t.Fatalf("index: status %d body %s", rec.Code, rec.Body)
+	}
+
+	var notes []Note
+	rec = get("/api/notes", "Authorization", "Bearer s3cret")
+	if err := json.Unmarshal(rec.Body.Bytes(), &notes); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(notes) != 2 || notes[0].Title != "Secret" || !notes[0].Encrypted || notes[1].ID != journal.NoteID(filename) {
+		t.Fatalf("notes = %+v", notes)
+	}
+
+	var note Note
+	rec = get("/api/notes/"+notes[0].ID, "Authorization", "Bearer s3cret")
+	if err := json.Unmarshal(rec.Body.Bytes(), &note); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !note.Locked || note.Content != "" {
+		t.Fatalf("encrypted note without key = %+v", note)
+	}
+
+	rec = get("/notes/"+notes[1].ID, "Authorization", "Bearer s3cret")
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "<b>basil</b>") {
+		t.Fatalf("note page should not pass raw HTML through: %s", rec.Body)
+	}
+	if rec := get("/api/notes/..%2Fconf", "Authorization", "Bearer s3cret"); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown note: status %d", rec.Code)
+	}
+
+	var results []SearchResult
+	rec = get("/api/search?q=basil", "Authorization", "Bearer s3cret")
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(results) != 1 || results[0].Note.Title != "Garden" || results[0].Line != 1 {
+		t.Fatalf("results = %+v", results)
+	}
+}
@@ -1,14 +1,26 @@
 package app
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/never00rei/a7/config"
 	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/lock"
+	"github.com/never00rei/a7/journal/templates"
 	"github.com/never00rei/a7/ui/components"
+	"github.com/never00rei/a7/ui/keys"
 	"github.com/never00rei/a7/ui/layout"
+	"github.com/never00rei/a7/ui/screens"
+	"github.com/never00rei/a7/ui/theme"
 )
 
 type screenID int
@@ -22,54 +34,115 @@ const (
 	screenDashboard
 	screenViewer
 	screenEditor
+	screenTemplatePicker
+	screenPromptPicker
+	screenCalendar
+	screenStats
+	screenTasks
 )
 
 type AppModel struct {
-	screen    screenID
-	width     int
-	height    int
-	config    ConfigState
-	welcome   WelcomeModel
-	storage   StorageModel
-	privacy   PrivacyModel
-	setup     SetupModel
-	settings  SettingsModel
-	dashboard DashboardModel
-	viewer    ViewerModel
-	editor    EditorModel
-	lastError error
+	screen         screenID
+	width          int
+	height         int
+	config         ConfigState
+	welcome        WelcomeModel
+	storage        StorageModel
+	privacy        PrivacyModel
+	setup          SetupModel
+	settings       SettingsModel
+	dashboard      DashboardModel
+	viewer         ViewerModel
+	editor         EditorModel
+	templatePicker TemplatePickerModel
+	promptPicker   PromptPickerModel
+	calendar       CalendarModel
+	stats          StatsModel
+	tasks          TasksModel
+	palette        PaletteModel
+	notes          *noteCache
+	watcher        *journal.Watcher
+	journalLock    *lock.Lock
+	claiming       string
+	sharedWith     *lock.Info
+	keys           keys.KeyMap
+	template       *templates.Template
+	showHelp       bool
+	tempDir        string
+	lastError      error
+}
+
+type Option func(*AppModel)
+
+// WithTemplate opens the editor seeded from tmpl once the journal is
+// configured and its notes have loaded, so prompts can avoid recent ones.
+func WithTemplate(tmpl templates.Template) Option {
+	return func(m *AppModel) {
+		if m.screen == screenDashboard {
+			m.template = &tmpl
+		}
+	}
 }
 
-func NewAppModel() AppModel {
+func NewAppModel(opts ...Option) AppModel {
 	model := AppModel{
 		screen: screenWelcome,
+		notes:  newNoteCache(noteCacheBytes),
 		config: ConfigState{
 			SshKeyPath: config.SshPath,
+			SortBy:     journal.SortUpdated,
+			SortDesc:   true,
+			GroupBy:    components.GroupNone,
+			Theme:      theme.Auto,
 		},
+		keys: keys.Default(),
 	}
+	var keysErr error
 	if conf, err := config.LoadConf(); err == nil && conf.JournalPath != "" {
 		model.config.StoragePath = conf.JournalPath
 		model.config.SshKeyPath = conf.SshKeyFile
 		model.config.SshPubKeyPath = conf.SshPubKey
 		model.config.Encrypt = conf.Encrypt
+		model.config.SortBy = journal.ParseSortField(conf.SortBy)
+		model.config.SortDesc = conf.SortDesc
+		model.config.GroupBy = components.ParseGroupMode(conf.GroupBy)
+		model.config.Theme = conf.Theme
+		model.keys, keysErr = keys.Load(conf.KeyPreset, conf.Keys)
 		model.screen = screenDashboard
 	}
 	model.storage.Form = components.NewStorageForm(&model.config.StoragePath, 0)
 	model.privacy.Form = components.NewPrivacyForm(&model.config.Encrypt, &model.config.SshKeyPath, &model.config.SshPubKeyPath, 0)
-	model.settings.Form = components.NewSettingsForm(&model.config.StoragePath, &model.config.Encrypt, &model.config.SshKeyPath, &model.config.SshPubKeyPath, 0)
+	model.settings.Form = components.NewSettingsForm(&model.config.StoragePath, &model.config.Encrypt, &model.config.SshKeyPath, &model.config.SshPubKeyPath, &model.config.Theme, themeNames(), 0)
 	model.dashboard.List = components.NewNotesList(nil, 0, 0)
 	model.viewer.Viewport = viewport.New(0, 0)
+	model.viewer.Find = textinput.New()
+	model.viewer.Find.Prompt = "/"
+	model.viewer.Outline = components.NewOutlineList(0, 0)
+	model.stats.Viewport = viewport.New(0, 0)
+	model.editor.PreviewView = viewport.New(0, 0)
 	model.editor.Title = textinput.New()
 	model.editor.Title.Placeholder = "Journal title"
 	model.editor.Body = textarea.New()
 	model.editor.Body.Placeholder = "Start writing..."
 	model.editor.Body.CharLimit = 0
+	model.templatePicker.List = components.NewPickerList(nil, 0, 0)
+	model.promptPicker.List = components.NewPickerList(nil, 0, 0)
+	model.calendar.DayList = components.NewPickerList(nil, 0, 0)
+	model.tasks.List = components.NewPickerList(nil, 0, 0)
+	model.applyKeys()
+	model.applyTheme()
+	if keysErr != nil {
+		model.dashboard.Status = fmt.Sprintf("Key settings: %v", keysErr)
+	}
+	for _, opt := range opts {
+		opt(&model)
+	}
 	return model
 }
 
 func (m AppModel) Init() tea.Cmd {
 	if m.screen == screenDashboard {
-		return m.loadDashboardNotesCmd()
+		return tea.Batch(m.loadDashboardNotesCmd(), m.loadDraftsCmd())
 	}
 	if model := m.activeScreenModel(); model != nil {
 		return model.Init(&m)
@@ -78,18 +151,104 @@ func (m AppModel) Init() tea.Cmd {
 }
 
 func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.KeyMsg); ok && m.typing() {
+		bound := m.keys
+		m.keys = bound.ForTyping()
+		updated, cmd := m.update(msg)
+		next := updated.(AppModel)
+		next.keys = bound
+		return next, cmd
+	}
+	return m.update(msg)
+}
+
+func (m AppModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m = m.updateFormWidths()
 		m = m.updateDashboardListSize()
+		m = m.updateTemplatePickerSize()
+		m = m.updatePromptPickerSize()
+		m = m.updateCalendarListSize()
+		m = m.updateTasksSize()
 		m = *m.updateViewerSize()
 		m = *m.updateEditorSize()
+		m = *m.updateStatsSize()
+		if m.editor.Preview {
+			return m, m.renderEditorPreviewCmd()
+		}
+	case editorPreviewTickMsg:
+		if msg.seq == m.editor.PreviewSeq && m.editor.Preview {
+			return m, m.renderEditorPreviewCmd()
+		}
+		return m, nil
+	case editorPreviewMsg:
+		m.applyEditorPreview(msg)
+		return m, nil
+	case editorSavedMsg:
+		return m.applyEditorSaved(msg)
+	case taskToggledMsg:
+		return m, m.applyTaskToggled(msg)
+	case hooksFailedMsg:
+		if m.screen == screenViewer {
+			m.viewer.Status = msg.failures.status()
+		} else {
+			m.dashboard.Status = msg.failures.status()
+		}
+		return m, nil
+	case editorAutosaveMsg:
+		return m, m.autosaveDraftCmd()
+	case draftSavedMsg:
+		if msg.err != nil {
+			m.editor.Err = fmt.Errorf("autosave draft: %w", msg.err)
+		}
+		return m, nil
+	case draftsMsg:
+		if msg.err != nil {
+			m.dashboard.Status = fmt.Sprintf("Unable to read drafts: %v", msg.err)
+			return m, nil
+		}
+		m.dashboard.Drafts = msg.drafts
+		if len(msg.unreadable) > 0 {
+			m.dashboard.Status = fmt.Sprintf("Unable to read drafts: %s", strings.Join(msg.unreadable, ", "))
+		}
+		return m, nil
 	case dashboardNotesMsg:
-		return m.applyDashboardNotes(msg), nil
+		return m.applyDashboardNotes(msg)
+	case selectionLoadedMsg:
+		m.applySelection(msg)
+		return m, nil
+	case backlinksMsg:
+		m.applyBacklinks(msg)
+		return m, nil
+	case watchStartedMsg:
+		return m, m.applyWatchStarted(msg)
+	case journalClaimedMsg:
+		return m, m.applyJournalClaimed(msg)
+	case journalChangedMsg:
+		return m, m.applyJournalChanges(msg)
 	case configSavedMsg:
 		return m, nil
+	case statusMsg:
+		m.dashboard.Status = msg.text
+		return m, nil
+	case rekeyMsg:
+		return m, m.applyRekey(msg)
+	case attachmentOpenedMsg:
+		if msg.err != nil {
+			m.viewer.Status = fmt.Sprintf("Unable to open %s: %v", msg.name, msg.err)
+		} else {
+			m.viewer.Status = "Opened " + msg.name
+		}
+		return m, nil
+	case migrationMsg:
+		m.dashboard.Status = formatMigrationStatus(msg)
+		if m.screen == screenDashboard {
+			return m, m.loadDashboardNotesCmd()
+		}
+		return m, nil
 	case errMsg:
 		m.lastError = msg.err
 		return m, nil
@@ -111,6 +270,26 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.initActiveFormCmd()
 	}
 
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if m.palette.Open {
+			return m, m.updatePalette(keyMsg)
+		}
+		if key.Matches(keyMsg, m.keys.Palette) && m.paletteAvailable() {
+			return m, m.openPalette()
+		}
+		if m.showHelp {
+			m.showHelp = false
+			if key.Matches(keyMsg, m.keys.Quit) {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if key.Matches(keyMsg, m.keys.Help) && m.helpOverlayAvailable() {
+			m.showHelp = true
+			return m, nil
+		}
+	}
+
 	var cmds []tea.Cmd
 	if model := m.activeScreenModel(); model != nil {
 		cmd, handled := model.Update(&m, msg)
@@ -122,39 +301,66 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		if m.screen == screenWalkthroughPrivacy && msg.String() == "s" {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		keys := m.keys
+		if m.screen == screenWalkthroughPrivacy && keyMsg.String() == "s" {
 			m.config.Encrypt = false
 			m.config.SshKeyPath = ""
 			m.config.SshPubKeyPath = ""
 			m.screen = screenSetup
 			return m, m.initActiveFormCmd()
 		}
-		if m.screen == screenDashboard {
-			switch msg.String() {
-			case "enter":
+		if m.screen == screenDashboard && m.dashboard.List.FilterState() != list.Filtering {
+			switch {
+			case key.Matches(keyMsg, keys.Open):
 				return m.openViewer()
-			case "s":
+			case key.Matches(keyMsg, keys.Settings):
 				m.screen = screenSettings
 				return m, m.initActiveFormCmd()
-			case "n":
-				m.startEditorForNew()
+			case key.Matches(keyMsg, keys.New):
+				m.openTemplatePicker()
+				return m, nil
+			case key.Matches(keyMsg, keys.Prompt):
+				m.openPromptPicker()
 				return m, nil
-			case "e":
-				m.startEditorForSelected()
+			case key.Matches(keyMsg, keys.Calendar):
+				m.openCalendar()
 				return m, nil
+			case key.Matches(keyMsg, keys.Stats):
+				m.openStats()
+				return m, nil
+			case key.Matches(keyMsg, keys.Tasks):
+				m.openTasks()
+				return m, nil
+			case key.Matches(keyMsg, keys.Sort):
+				m.config.SortBy = m.config.SortBy.Next()
+				m.refreshDashboardItems()
+				return m, m.saveConfigCmd()
+			case key.Matches(keyMsg, keys.Reverse):
+				m.config.SortDesc = !m.config.SortDesc
+				m.refreshDashboardItems()
+				return m, m.saveConfigCmd()
+			case key.Matches(keyMsg, keys.Group):
+				m.config.GroupBy = m.config.GroupBy.Next()
+				m.refreshDashboardItems()
+				return m, m.saveConfigCmd()
+			case key.Matches(keyMsg, keys.Edit):
+				return m, m.startEditorForSelected()
 			}
 		}
-		switch msg.String() {
-		case "esc":
-			if m.screen == screenViewer || m.screen == screenEditor || m.screen == screenSettings {
+		switch {
+		case key.Matches(keyMsg, keys.Back):
+			if m.screen == screenViewer {
+				m.screen = m.viewerBackScreen()
+				return m, nil
+			}
+			if m.screen == screenEditor || m.screen == screenSettings {
 				m.screen = screenDashboard
 				return m, nil
 			}
-		case "ctrl+c":
+		case key.Matches(keyMsg, keys.Quit):
 			return m, tea.Quit
-		case "enter":
+		case key.Matches(keyMsg, keys.Open):
 			if m.screen == screenSetup {
 				cmd := tea.Sequence(m.saveConfigCmd(), func() tea.Msg {
 					return screenDashboard
@@ -163,23 +369,18 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.screen = nextScreen(m.screen)
 			return m, m.initActiveFormCmd()
-		case "e":
-			if m.screen == screenViewer {
-				m.startEditorForViewer()
-				return m, nil
+		case key.Matches(keyMsg, keys.Edit) && m.screen == screenViewer:
+			return m, m.startEditorForViewer()
+		case key.Matches(keyMsg, keys.NextField) && m.screen == screenEditor:
+			if m.editor.Title.Focused() {
+				m.editor.Title.Blur()
+				m.editor.Body.Focus()
+			} else {
+				m.editor.Body.Blur()
+				m.editor.Title.Focus()
 			}
-		case "tab":
-			if m.screen == screenEditor {
-				if m.editor.Title.Focused() {
-					m.editor.Title.Blur()
-					m.editor.Body.Focus()
-				} else {
-					m.editor.Body.Blur()
-					m.editor.Title.Focus()
-				}
-				return m, nil
-			}
-		case "shift+tab":
+			return m, nil
+		case key.Matches(keyMsg, keys.PrevField):
 			if m.screen == screenEditor {
 				if m.editor.Body.Focused() {
 					m.editor.Body.Blur()
@@ -191,15 +392,13 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			if m.screen == screenViewer {
-				m.screen = screenDashboard
+				m.screen = m.viewerBackScreen()
 				return m, nil
 			}
 			m.screen = prevScreen(m.screen)
 			return m, m.initActiveFormCmd()
-		case "ctrl+s":
-			if m.screen == screenEditor {
-				return m.saveEditorNote()
-			}
+		case key.Matches(keyMsg, keys.Save) && m.screen == screenEditor:
+			return m.saveEditorNote()
 		}
 	}
 
@@ -219,6 +418,10 @@ func (m AppModel) saveConfigCmd() tea.Cmd {
 			sshPubKeyPath = ""
 		}
 		conf := config.NewConf(journalPath, sshKeyPath, sshPubKeyPath, m.config.Encrypt)
+		conf.SortBy = string(m.config.SortBy)
+		conf.SortDesc = m.config.SortDesc
+		conf.GroupBy = string(m.config.GroupBy)
+		conf.Theme = m.config.Theme
 		if err := conf.SaveConfig(); err != nil {
 			return errMsg{err: err}
 		}
@@ -278,9 +481,7 @@ func (m AppModel) batchFormCmd(cmd tea.Cmd, previous screenID) tea.Cmd {
 func (m AppModel) resetDashboardNotes() AppModel {
 	m.dashboard.Err = nil
 	m.dashboard.Notes = nil
-	m.dashboard.SelectedNote = nil
-	m.dashboard.SelectedErr = nil
-	m.dashboard.SelectedFilename = ""
+	m.clearDashboardSelection()
 	m.dashboard.List.SetItems(nil)
 	m.dashboard.List.Title = ""
 	return m
@@ -298,55 +499,178 @@ func (m AppModel) loadDashboardNotesCmd() tea.Cmd {
 	}
 }
 
-func (m AppModel) applyDashboardNotes(msg dashboardNotesMsg) AppModel {
+func (m AppModel) applyDashboardNotes(msg dashboardNotesMsg) (AppModel, tea.Cmd) {
 	if msg.path != m.config.StoragePath {
-		return m
+		return m, nil
+	}
+	if m.watcher != nil && m.watcher.Root != msg.path {
+		m.stopWatching()
 	}
 	m.dashboard.Err = msg.err
 	if msg.err != nil {
 		m.dashboard.Notes = nil
 		m.dashboard.List.SetItems(nil)
 		m.dashboard.List.Title = ""
-		return m
+		m.startPendingTemplate()
+		return m, nil
 	}
 
+	claim := m.claimJournalCmd()
 	m.dashboard.Notes = msg.notes
-	m.dashboard.List.SetItems(components.BuildNoteItems(msg.notes))
-	m.dashboard.List.Title = m.config.StoragePath
-	if len(msg.notes) > 0 {
-		m.dashboard.List.Select(0)
-	}
+	m.refreshDashboardItems()
 	m = m.updateDashboardListSize()
-	m.updateDashboardSelection()
-	return m
+	m.startPendingTemplate()
+	return m, tea.Batch(claim, m.updateDashboardSelection(), m.startWatchCmd())
+}
+
+// startPendingTemplate opens the editor for the template passed to
+// WithTemplate, unless the user has already left the dashboard.
+func (m *AppModel) startPendingTemplate() {
+	tmpl := m.template
+	m.template = nil
+	if tmpl != nil && m.screen == screenDashboard {
+		m.startEditorForTemplate(*tmpl)
+	}
 }
 
-func (m *AppModel) updateDashboardSelection() {
-	if m.config.StoragePath == "" {
-		m.dashboard.SelectedNote = nil
-		m.dashboard.SelectedErr = nil
-		m.dashboard.SelectedFilename = ""
+func (m *AppModel) refreshDashboardItems() {
+	selected := ""
+	if item, ok := m.dashboard.List.SelectedItem().(components.NoteItem); ok {
+		selected = item.Info.Filename
+	}
+
+	journal.SortNotes(m.dashboard.Notes, m.config.SortBy, m.config.SortDesc)
+	items := components.BuildGroupedNoteItems(m.dashboard.Notes, m.config.GroupBy, m.config.SortBy, m.config.SortDesc)
+	m.dashboard.List.SetItems(items)
+	m.dashboard.List.Title = m.dashboardListTitle()
+	if len(items) == 0 {
 		return
 	}
+	if selected == "" || !components.SelectNote(&m.dashboard.List, selected) {
+		m.dashboard.List.Select(0)
+	}
+	components.SkipHeaders(&m.dashboard.List, true)
+}
+
+func (m AppModel) dashboardListTitle() string {
+	direction := "↑"
+	if m.config.SortDesc {
+		direction = "↓"
+	}
+	title := fmt.Sprintf("%s • %s %s", m.config.StoragePath, m.config.SortBy, direction)
+	if m.config.GroupBy != components.GroupNone {
+		title += " • by " + string(m.config.GroupBy)
+	}
+	if m.sharedWith != nil {
+		title += " • shared"
+	}
+	return title
+}
 
+// updateDashboardSelection shows the selected note's details, from the
+// cache when possible. Otherwise the note is loaded in the background and
+// any load still running for an earlier selection is cancelled.
+func (m *AppModel) updateDashboardSelection() tea.Cmd {
 	item := m.dashboard.List.SelectedItem()
 	noteItem, ok := item.(components.NoteItem)
-	if !ok {
-		m.dashboard.SelectedNote = nil
-		m.dashboard.SelectedErr = nil
-		m.dashboard.SelectedFilename = ""
-		return
+	if m.config.StoragePath == "" || !ok {
+		m.clearDashboardSelection()
+		return nil
+	}
+	info := noteItem.Info
+	if info.Filename == m.dashboard.SelectedFilename && info.ModTime.Equal(m.dashboard.SelectedModTime) {
+		return nil
+	}
+
+	m.cancelSelectionLoad()
+	m.dashboard.SelectedFilename = info.Filename
+	m.dashboard.SelectedModTime = info.ModTime
+	m.dashboard.SelectedErr = nil
+	if loaded, ok := m.notes.Get(info); ok {
+		m.dashboard.SelectedNote = loaded.note
+		m.dashboard.SelectedAttachments = loaded.attachments
+		return nil
 	}
 
+	m.dashboard.SelectedNote = nil
+	m.dashboard.SelectedAttachments = 0
+	m.dashboard.SelectedLoading = true
+	m.dashboard.SelectionSeq++
+	seq := m.dashboard.SelectionSeq
+	ctx, cancel := context.WithCancel(context.Background())
+	m.dashboard.SelectionCancel = cancel
 	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
-	note, err := service.LoadNote(noteItem.Info.Filename)
-	m.dashboard.SelectedFilename = noteItem.Info.Filename
-	m.dashboard.SelectedNote = note
-	m.dashboard.SelectedErr = err
+	return func() tea.Msg {
+		if ctx.Err() != nil {
+			return nil
+		}
+		note, err := service.LoadNote(info.Filename)
+		attachments, _ := service.ListAttachments(info.Filename)
+		if ctx.Err() != nil {
+			return nil
+		}
+		loaded := loadedNote{note: note, attachments: len(attachments)}
+		return selectionLoadedMsg{seq: seq, info: info, loaded: loaded, err: err}
+	}
+}
+
+func (m *AppModel) applySelection(msg selectionLoadedMsg) {
+	if msg.seq != m.dashboard.SelectionSeq {
+		return
+	}
+	m.dashboard.SelectionCancel = nil
+	m.dashboard.SelectedLoading = false
+	m.dashboard.SelectedNote = msg.loaded.note
+	m.dashboard.SelectedAttachments = msg.loaded.attachments
+	m.dashboard.SelectedErr = msg.err
+	if msg.err == nil && msg.loaded.note != nil {
+		m.notes.Put(msg.info, msg.loaded)
+	}
+}
+
+func (m *AppModel) cancelSelectionLoad() {
+	if m.dashboard.SelectionCancel != nil {
+		m.dashboard.SelectionCancel()
+		m.dashboard.SelectionCancel = nil
+	}
+	m.dashboard.SelectionSeq++
+	m.dashboard.SelectedLoading = false
+}
+
+func (m *AppModel) clearDashboardSelection() {
+	m.cancelSelectionLoad()
+	m.dashboard.SelectedNote = nil
+	m.dashboard.SelectedErr = nil
+	m.dashboard.SelectedAttachments = 0
+	m.dashboard.SelectedFilename = ""
+	m.dashboard.SelectedModTime = time.Time{}
+}
+
+// lockJournal forgets every decrypted note held in memory and closes an
+// encrypted note open in the viewer. The editor is left alone so unsaved
+// text is never lost.
+func (m *AppModel) lockJournal() {
+	m.notes.Clear()
+	m.clearDashboardSelection()
+	if m.viewer.Note != nil && m.viewer.Note.Encrypted {
+		m.viewer.Note = nil
+		m.viewer.Raw = ""
+		m.viewer.Rendered = ""
+		m.viewer.Viewport.SetContent("")
+		if m.screen == screenViewer {
+			m.screen = screenDashboard
+		}
+	}
 }
 
 func (m AppModel) View() string {
 	layout := m.layout()
+	if m.showHelp {
+		return layout.Frame(screens.Help(layout, components.FormatKeyHelp(m.keys.FullHelp())), "any key close")
+	}
+	if m.palette.Open {
+		return layout.Frame(screens.Palette(layout, m.paletteView(layout)), m.paletteHelp())
+	}
 	model := m.activeScreenModel()
 	if model == nil {
 		return layout.Frame("unknown screen", m.helpText())
@@ -354,27 +678,6 @@ func (m AppModel) View() string {
 	return layout.Frame(model.View(&m, layout), m.helpText())
 }
 
-func (m AppModel) helpText() string {
-	switch m.screen {
-	case screenWelcome:
-		return "enter begin • ctrl+c quit"
-	case screenWalkthroughStorage:
-		return "⏎/enter/tab next • shift+tab back • ctrl+c quit"
-	case screenWalkthroughPrivacy:
-		return "⏎/enter/tab next • shift+tab back • s skip • ctrl+c quit"
-	case screenDashboard:
-		return "↑/k up • ↓/j down • / filter • ⏎/enter view • n new • e edit • s settings • ctrl+c quit"
-	case screenViewer:
-		return "esc back • e edit • ctrl+c quit"
-	case screenEditor:
-		return "tab switch • ctrl+s save • esc back • ctrl+c quit"
-	case screenSettings:
-		return "tab next • shift+tab back • esc back • ctrl+c quit"
-	default:
-		return "⏎/enter continue • shift+tab back • ctrl+c quit"
-	}
-}
-
 func nextScreen(current screenID) screenID {
 	switch current {
 	case screenWelcome:
@@ -1,13 +1,19 @@
 package app
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/never00rei/a7/config"
 	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/codec"
+	"github.com/never00rei/a7/journal/templates"
 	"github.com/never00rei/a7/ui/components"
 )
 
@@ -20,7 +26,9 @@ func setupTestConfig(t *testing.T) {
 	config.Home = temp
 	config.XdgConfigHome = temp
 	config.SshPath = filepath.Join(temp, ".ssh")
+	watchJournals = false
 	t.Cleanup(func() {
+		watchJournals = true
 		config.Home = origHome
 		config.XdgConfigHome = origXdg
 		config.SshPath = origSsh
@@ -33,6 +41,14 @@ func applyCmd(model AppModel, cmd tea.Cmd) AppModel {
 		if msg == nil {
 			break
 		}
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, cmd := range batch {
+				if cmd != nil {
+					model = applyCmd(model, cmd)
+				}
+			}
+			break
+		}
 		updated, nextCmd := model.Update(msg)
 		model = updated.(AppModel)
 		cmd = nextCmd
@@ -164,7 +180,7 @@ func TestDashboardSelectionLoadsMetadata(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListNotes: %v", err)
 	}
-	model = model.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes})
+	model = applyCmd(model.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes}))
 	if model.dashboard.SelectedNote == nil {
 		t.Fatalf("selected note is nil")
 	}
@@ -173,6 +189,245 @@ func TestDashboardSelectionLoadsMetadata(t *testing.T) {
 	}
 }
 
+func TestDashboardSelectionLoadsInBackgroundAndCaches(t *testing.T) {
+	setupTestConfig(t)
+	root := t.TempDir()
+	svc := journal.NewService(root)
+	now := time.Now()
+	for i, title := range []string{"First", "Second"} {
+		if _, err := svc.SaveNote(title, "body of "+title, now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("SaveNote: %v", err)
+		}
+	}
+	notes, err := svc.ListNotes()
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	model := NewAppModel()
+	model.screen = screenDashboard
+	model.config.StoragePath = root
+	model.dashboard.List = components.NewNotesList(nil, 0, 0)
+
+	model, firstLoad := model.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes})
+	if !model.dashboard.SelectedLoading || model.dashboard.SelectedNote != nil || firstLoad == nil {
+		t.Fatalf("selection should load in the background")
+	}
+	if !strings.Contains(model.View(), "Loading entry") {
+		t.Fatalf("metadata pane should show the loading indicator")
+	}
+	stale := firstLoad()
+
+	updated, secondLoad := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model = updated.(AppModel)
+	updated, _ = model.Update(stale)
+	model = updated.(AppModel)
+	if model.dashboard.SelectedNote != nil {
+		t.Fatalf("stale load was applied to the new selection")
+	}
+	model = applyCmd(model, secondLoad)
+	second := model.dashboard.SelectedNote
+	if second == nil || model.dashboard.SelectedLoading {
+		t.Fatalf("second selection not loaded")
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
+	model = updated.(AppModel)
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model = updated.(AppModel)
+	if model.dashboard.SelectedNote != second || model.dashboard.SelectedLoading {
+		t.Fatalf("returning to a loaded note should use the cache")
+	}
+
+	model.lockJournal()
+	if model.notes.Len() != 0 || model.dashboard.SelectedNote != nil {
+		t.Fatalf("lock should clear decrypted notes")
+	}
+
+	cache := newNoteCache(10)
+	a := journal.NoteInfo{Filename: "a.md"}
+	b := journal.NoteInfo{Filename: "b.md"}
+	cache.Put(a, loadedNote{note: &journal.Note{Content: "123456"}})
+	cache.Put(b, loadedNote{note: &journal.Note{Content: "123456"}})
+	if _, ok := cache.Get(a); ok || cache.Len() != 1 {
+		t.Fatalf("least recently used note should be evicted once over budget")
+	}
+}
+
+func TestDashboardMetaShowsPreviewAndLockedPlaceholder(t *testing.T) {
+	setupTestConfig(t)
+	root := t.TempDir()
+	svc := journal.NewService(root)
+	now := time.Now()
+	filename, err := svc.SaveNote("Walk", "# Morning\n\nA long **walk** by the [[River]] #outside", now, journal.WithTags("outside"))
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	src := filepath.Join(t.TempDir(), "map.txt")
+	if err := os.WriteFile(src, []byte("route"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := svc.AddAttachment(filename, src); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+	secret := codec.RenderContent(codec.FrontMatter{Title: "Secret", Created: now.Add(time.Minute), Encrypted: true, WordCount: 3},
+		"-----BEGIN AGE ENCRYPTED FILE-----\nnot really\n-----END AGE ENCRYPTED FILE-----\n")
+	if err := os.WriteFile(filepath.Join(root, codec.BuildFilename("Secret", now.Add(time.Minute))), []byte(secret), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := config.NewConf(root, "", "", false).SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+
+	model := NewAppModel()
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 50})
+	model = applyCmd(updated.(AppModel), model.loadDashboardNotesCmd())
+	model.config.SortBy = journal.SortCreated
+	model.config.SortDesc = false
+	model.refreshDashboardItems()
+	model.dashboard.List.Select(0)
+	model = applyCmd(model, model.updateDashboardSelection())
+
+	view := ansi.Strip(model.View())
+	for _, want := range []string{"Preview", "A long walk by the River", "1 min read", "#outside", "Attachments"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("dashboard missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "**walk**") || strings.Contains(view, "[[River]]") {
+		t.Fatalf("preview should drop Markdown syntax:\n%s", view)
+	}
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model = applyCmd(updated.(AppModel), cmd)
+	if view := ansi.Strip(model.View()); !strings.Contains(view, "Locked") {
+		t.Fatalf("encrypted entry without a key should show a locked preview:\n%s", view)
+	}
+}
+
+func TestSecondInstanceSharesLockedJournal(t *testing.T) {
+	setupTestConfig(t)
+	root, _ := createTestJournal(t)
+	notes, err := journal.NewService(root).ListNotes()
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	open := func() AppModel {
+		model := NewAppModel()
+		model.screen = screenDashboard
+		model.config.StoragePath = root
+		model, cmd := model.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes})
+		return applyCmd(model, cmd)
+	}
+
+	first := open()
+	if first.journalLock == nil || first.sharedWith != nil {
+		t.Fatalf("first instance did not lock the journal")
+	}
+	held := first.journalLock
+	first.config.StoragePath = root + string(filepath.Separator)
+	first, cmd := first.applyDashboardNotes(dashboardNotesMsg{path: first.config.StoragePath, notes: notes})
+	first = applyCmd(first, cmd)
+	if first.journalLock != held || first.sharedWith != nil {
+		t.Fatalf("the same journal with a trailing slash should keep its lock")
+	}
+	second := open()
+	if second.journalLock != nil || second.sharedWith == nil {
+		t.Fatalf("second instance should share the journal")
+	}
+	if !strings.Contains(second.dashboard.Status, "also open in another a7") || !strings.HasSuffix(second.dashboard.List.Title, "• shared") {
+		t.Fatalf("status = %q title = %q", second.dashboard.Status, second.dashboard.List.Title)
+	}
+
+	first.Cleanup()
+	if _, err := os.Stat(filepath.Join(root, journal.LockFile)); !os.IsNotExist(err) {
+		t.Fatalf("journal lock not released: %v", err)
+	}
+	second, cmd = second.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes})
+	second = applyCmd(second, cmd)
+	if second.journalLock == nil {
+		t.Fatalf("journal not claimed once the other instance exited")
+	}
+	second.Cleanup()
+}
+
+func TestWatcherUpdatesDashboardAndWarnsEditor(t *testing.T) {
+	setupTestConfig(t)
+	watchJournals = true
+	root, filename := createTestJournal(t)
+	svc := journal.NewService(root)
+	notes, err := svc.ListNotes()
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	model := NewAppModel()
+	model.screen = screenDashboard
+	model.config.StoragePath = root
+	model, _ = model.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes})
+	updated, wait := model.Update(model.startWatchCmd()())
+	model = updated.(AppModel)
+	if model.watcher == nil || wait == nil {
+		t.Fatalf("watcher not started")
+	}
+	t.Cleanup(func() { model.watcher.Close() })
+
+	next := func(wait tea.Cmd) (AppModel, tea.Cmd) {
+		t.Helper()
+		msgs := make(chan tea.Msg, 1)
+		go func() { msgs <- wait() }()
+		select {
+		case msg := <-msgs:
+			updated, cmd := model.Update(msg)
+			return updated.(AppModel), cmd
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for journal changes")
+			return model, nil
+		}
+	}
+
+	added, err := svc.SaveNote("From capture", "written elsewhere", time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	model, wait = next(wait)
+	if len(model.dashboard.Notes) != 2 || len(model.dashboard.List.Items()) != 2 {
+		t.Fatalf("dashboard notes = %+v, want the captured note added", model.dashboard.Notes)
+	}
+
+	components.SelectNote(&model.dashboard.List, filename)
+	model.startEditorForSelected()
+	if model.screen != screenEditor || model.editor.File != filename {
+		t.Fatalf("editor not open on %s", filename)
+	}
+	if err := svc.UpdateNote(filename, "Test Journal", "changed by sync", time.Now()); err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	if err := os.Remove(filepath.Join(root, added)); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	model, _ = next(wait)
+	if len(model.dashboard.Notes) != 1 {
+		t.Fatalf("removed note still listed: %+v", model.dashboard.Notes)
+	}
+	if model.editor.DiskWarning == "" || !strings.Contains(model.helpText(), "changed on disk") {
+		t.Fatalf("editor should warn about the change on disk, help %q", model.helpText())
+	}
+
+	watcher := model.watcher
+	model.Cleanup()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-watcher.Changes:
+			if ok {
+				continue
+			}
+		case <-deadline:
+			t.Fatalf("watcher still running after Cleanup")
+		}
+		break
+	}
+}
+
 func TestDashboardEnterOpensViewer(t *testing.T) {
 	setupTestConfig(t)
 	root, _ := createTestJournal(t)
@@ -186,7 +441,7 @@ func TestDashboardEnterOpensViewer(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListNotes: %v", err)
 	}
-	model = model.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes})
+	model = applyCmd(model.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes}))
 
 	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
 	next := updated.(AppModel)
@@ -200,3 +455,719 @@ func TestDashboardEnterOpensViewer(t *testing.T) {
 		t.Fatalf("viewer note missing")
 	}
 }
+
+func TestDashboardNewOpensTemplatePicker(t *testing.T) {
+	setupTestConfig(t)
+	model := NewAppModel()
+	model.screen = screenDashboard
+	model.config.StoragePath = t.TempDir()
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	next := updated.(AppModel)
+	if next.screen != screenTemplatePicker {
+		t.Fatalf("after n screen = %v, want %v", next.screen, screenTemplatePicker)
+	}
+	if len(next.templatePicker.List.Items()) < 2 {
+		t.Fatalf("template picker items = %d, want blank plus built-ins", len(next.templatePicker.List.Items()))
+	}
+
+	next.templatePicker.List.Select(1)
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next = updated.(AppModel)
+	if next.screen != screenEditor {
+		t.Fatalf("after enter screen = %v, want %v", next.screen, screenEditor)
+	}
+	if next.editor.Title.Value() == "" || next.editor.Body.Value() == "" {
+		t.Fatalf("editor not seeded from template")
+	}
+}
+
+func TestWithTemplateWaitsForDashboardNotes(t *testing.T) {
+	setupTestConfig(t)
+	root, _ := createTestJournal(t)
+	model := NewAppModel()
+	model.screen = screenDashboard
+	model.config.StoragePath = root
+	WithTemplate(templates.Template{Name: "Daily", Title: "Daily", Body: "Notes"})(&model)
+	if model.screen != screenDashboard {
+		t.Fatalf("editor opened before notes loaded, screen = %v", model.screen)
+	}
+
+	svc := journal.NewService(root)
+	notes, err := svc.ListNotes()
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	model = applyCmd(model.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes}))
+	if model.screen != screenEditor || model.editor.Body.Value() != "Notes" {
+		t.Fatalf("screen = %v body = %q, want seeded editor", model.screen, model.editor.Body.Value())
+	}
+	if len(model.dashboard.Notes) != 1 {
+		t.Fatalf("dashboard notes = %d, want 1", len(model.dashboard.Notes))
+	}
+}
+
+func TestDashboardGroupsStayTogetherWhenSortedByTitle(t *testing.T) {
+	setupTestConfig(t)
+	root := t.TempDir()
+	svc := journal.NewService(root)
+	for title, created := range map[string]time.Time{
+		"Alpha":   time.Date(2024, 1, 5, 9, 0, 0, 0, time.Local),
+		"Bravo":   time.Date(2024, 3, 5, 9, 0, 0, 0, time.Local),
+		"Charlie": time.Date(2024, 1, 20, 9, 0, 0, 0, time.Local),
+	} {
+		if _, err := svc.SaveNote(title, "body", created); err != nil {
+			t.Fatalf("SaveNote: %v", err)
+		}
+	}
+	notes, err := svc.ListNotes()
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	model := NewAppModel()
+	model.screen = screenDashboard
+	model.config.StoragePath = root
+	model.config.SortBy = journal.SortTitle
+	model.config.SortDesc = false
+	model.config.GroupBy = components.GroupMonth
+	model = applyCmd(model.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes}))
+
+	var got []string
+	for _, item := range model.dashboard.List.Items() {
+		switch item := item.(type) {
+		case components.HeaderItem:
+			got = append(got, "["+item.Label+"]")
+		case components.NoteItem:
+			got = append(got, item.Info.Title)
+		}
+	}
+	want := "[March 2024] Bravo [January 2024] Alpha Charlie"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("items = %q, want %q", strings.Join(got, " "), want)
+	}
+}
+
+func TestCalendarOpensDayAndReturns(t *testing.T) {
+	setupTestConfig(t)
+	root, _ := createTestJournal(t)
+	model := NewAppModel()
+	model.screen = screenDashboard
+	model.config.StoragePath = root
+
+	svc := journal.NewService(root)
+	notes, err := svc.ListNotes()
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	model = applyCmd(model.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes}))
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	next := updated.(AppModel)
+	if next.screen != screenCalendar {
+		t.Fatalf("after c screen = %v, want %v", next.screen, screenCalendar)
+	}
+	if next.calendarDayCount() != 1 {
+		t.Fatalf("today entries = %d, want 1", next.calendarDayCount())
+	}
+
+	cursor := next.calendar.Cursor
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	next = updated.(AppModel)
+	if next.calendar.Cursor.Month() == cursor.Month() {
+		t.Fatalf("] did not move to the next month")
+	}
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	next = updated.(AppModel)
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next = updated.(AppModel)
+	if next.screen != screenViewer {
+		t.Fatalf("after enter screen = %v, want %v", next.screen, screenViewer)
+	}
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	next = updated.(AppModel)
+	if next.screen != screenCalendar {
+		t.Fatalf("viewer esc screen = %v, want %v", next.screen, screenCalendar)
+	}
+}
+
+func TestDashboardGroupingSkipsHeaders(t *testing.T) {
+	setupTestConfig(t)
+	root, _ := createTestJournal(t)
+	model := NewAppModel()
+	model.screen = screenDashboard
+	model.config.StoragePath = root
+	model.config.GroupBy = components.GroupMonth
+
+	svc := journal.NewService(root)
+	notes, err := svc.ListNotes()
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	model = applyCmd(model.applyDashboardNotes(dashboardNotesMsg{path: root, notes: notes}))
+
+	if _, ok := model.dashboard.List.Items()[0].(components.HeaderItem); !ok {
+		t.Fatalf("first item should be a group header")
+	}
+	if _, ok := model.dashboard.List.SelectedItem().(components.NoteItem); !ok {
+		t.Fatalf("selection should skip the group header")
+	}
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	next := updated.(AppModel)
+	if next.config.GroupBy != components.GroupNone {
+		t.Fatalf("group after y = %q, want %q", next.config.GroupBy, components.GroupNone)
+	}
+	if len(next.dashboard.List.Items()) != 1 {
+		t.Fatalf("items = %d, want 1 without headers", len(next.dashboard.List.Items()))
+	}
+}
+
+func TestMigrationReportShowsDashboardStatus(t *testing.T) {
+	setupTestConfig(t)
+	root, _ := createTestJournal(t)
+	model := NewAppModel()
+	model.screen = screenDashboard
+	model.config.StoragePath = root
+
+	model = applyCmd(model, model.migrateLegacyCmd())
+	if model.dashboard.Status == "" {
+		t.Fatalf("migration status missing")
+	}
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if status := updated.(AppModel).dashboard.Status; status != "" {
+		t.Fatalf("status after key = %q, want cleared", status)
+	}
+}
+
+func TestEditorDropAttachesFileToNewNote(t *testing.T) {
+	setupTestConfig(t)
+	root := t.TempDir()
+	model := NewAppModel()
+	model.config.StoragePath = root
+	model.startEditorForNew()
+	model.editor.Title.SetValue("With photo")
+	model.editor.Title.Blur()
+	model.editor.Body.Focus()
+
+	src := filepath.Join(t.TempDir(), "photo.png")
+	if err := os.WriteFile(src, []byte("png"), 0644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("'" + src + "'"), Paste: true})
+	model = updated.(AppModel)
+	if len(model.editor.Pending) != 1 || !strings.Contains(model.editor.Body.Value(), "![photo.png](attachments/pending/1/photo.png)") {
+		t.Fatalf("drop not linked: %q", model.editor.Body.Value())
+	}
+
+	model = applyCmd(model.saveEditorNote())
+	if model.editor.Err != nil {
+		t.Fatalf("save: %v", model.editor.Err)
+	}
+	svc := journal.NewService(root)
+	notes, err := svc.ListNotes()
+	if err != nil || len(notes) != 1 {
+		t.Fatalf("ListNotes = %v, %v", notes, err)
+	}
+	note, err := svc.LoadNote(notes[0].Filename)
+	if err != nil {
+		t.Fatalf("LoadNote: %v", err)
+	}
+	want := "](attachments/" + journal.NoteID(notes[0].Filename) + "/photo.png)"
+	if !strings.Contains(note.Content, want) {
+		t.Fatalf("saved body %q missing %q", note.Content, want)
+	}
+	if attachments, _ := svc.ListAttachments(notes[0].Filename); len(attachments) != 1 {
+		t.Fatalf("attachments = %v, want one", attachments)
+	}
+}
+
+func TestViewerFollowsWikiLinkAndReturns(t *testing.T) {
+	setupTestConfig(t)
+	root := t.TempDir()
+	svc := journal.NewService(root)
+	now := time.Now()
+	if _, err := svc.SaveNote("Target", "linked to", now); err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	source, err := svc.SaveNote("Source", "see [[Target]]", now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+
+	model := NewAppModel()
+	model.config.StoragePath = root
+	model, _ = model.openViewerForNote(journal.NoteInfo{Filename: source}, screenDashboard)
+	if len(model.viewer.Links) != 1 {
+		t.Fatalf("Links = %v, want one", model.viewer.Links)
+	}
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	updated, cmd := updated.(AppModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(AppModel)
+	if len(model.viewer.Backlinks) != 0 {
+		t.Fatalf("backlinks should load in the background")
+	}
+	model = applyCmd(model, cmd)
+	if model.viewer.Title != "Target" {
+		t.Fatalf("after follow title = %q, want Target", model.viewer.Title)
+	}
+	if len(model.viewer.Backlinks) != 1 || model.viewer.Backlinks[0].Filename != source {
+		t.Fatalf("Backlinks = %v, want %s", model.viewer.Backlinks, source)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(AppModel)
+	if model.screen != screenViewer || model.viewer.Title != "Source" {
+		t.Fatalf("esc should return to Source, got screen %v title %q", model.screen, model.viewer.Title)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if next := updated.(AppModel); next.screen != screenDashboard {
+		t.Fatalf("second esc screen = %v, want dashboard", next.screen)
+	}
+}
+
+func TestViewerStepsEntriesFindsAndJumpsToHeadings(t *testing.T) {
+	setupTestConfig(t)
+	root := t.TempDir()
+	svc := journal.NewService(root)
+	now := time.Now()
+	var body strings.Builder
+	body.WriteString("# Morning\n\n")
+	for i := 0; i < 40; i++ {
+		body.WriteString("filler line\n\n")
+	}
+	body.WriteString("## Evening\n\nthe needle is here\n\nanother needle\n")
+	if _, err := svc.SaveNote("Older", "older entry", now.Add(-time.Hour)); err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	if _, err := svc.SaveNote("Newer", body.String(), now); err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+	if err := config.NewConf(root, "", "", false).SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+
+	model := NewAppModel()
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	model = applyCmd(updated.(AppModel), model.loadDashboardNotesCmd())
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(AppModel)
+	if model.screen != screenViewer || model.viewer.Title != "Newer" {
+		t.Fatalf("viewer title = %q, want Newer", model.viewer.Title)
+	}
+
+	for _, r := range "/needle" {
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(AppModel)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(AppModel)
+	if model.viewer.Finding || len(model.viewer.Matches) != 2 {
+		t.Fatalf("matches = %v, want 2", model.viewer.Matches)
+	}
+	if model.viewer.Viewport.YOffset == 0 {
+		t.Fatalf("viewport did not scroll to the first match")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	model = updated.(AppModel)
+	if model.viewer.MatchIndex != 1 || !strings.Contains(model.helpText(), "match 2/2") {
+		t.Fatalf("n should move to the second match, help %q", model.helpText())
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(AppModel)
+	if model.screen != screenViewer || model.viewer.Query != "" {
+		t.Fatalf("esc should clear the search first")
+	}
+
+	model.viewer.Viewport.GotoBottom()
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	model = updated.(AppModel)
+	if !model.viewer.OutlineOpen || len(model.viewer.Outline.Items()) != 2 {
+		t.Fatalf("outline = %v", model.viewer.Outline.Items())
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(AppModel)
+	if model.viewer.OutlineOpen || model.viewer.Viewport.YOffset != 0 {
+		t.Fatalf("jumping to Morning should scroll to the top, offset %d", model.viewer.Viewport.YOffset)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	model = updated.(AppModel)
+	if model.screen != screenViewer || model.viewer.Title != "Older" {
+		t.Fatalf("] title = %q, want Older", model.viewer.Title)
+	}
+	if selected, ok := model.dashboard.List.SelectedItem().(components.NoteItem); !ok || selected.Info.Title != "Older" {
+		t.Fatalf("dashboard cursor did not follow the viewer")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	model = updated.(AppModel)
+	if model.viewer.Title != "Older" || !strings.Contains(model.helpText(), "No later entry") {
+		t.Fatalf("stepping past the end should stay put, help %q", model.helpText())
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("[")})
+	model = updated.(AppModel)
+	if model.viewer.Title != "Newer" {
+		t.Fatalf("[ title = %q, want Newer", model.viewer.Title)
+	}
+}
+
+func TestTasksScreenTogglesTask(t *testing.T) {
+	setupTestConfig(t)
+	root := t.TempDir()
+	svc := journal.NewService(root)
+	filename, err := svc.SaveNote("Todo", "- [ ] call the bank\n- [ ] buy milk", time.Now())
+	if err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+
+	model := NewAppModel()
+	model.screen = screenDashboard
+	model.config.StoragePath = root
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	model = updated.(AppModel)
+	if model.screen != screenTasks || len(model.tasks.List.Items()) != 2 {
+		t.Fatalf("screen = %v with %d tasks, want tasks screen with 2", model.screen, len(model.tasks.List.Items()))
+	}
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	model = applyCmd(updated.(AppModel), cmd)
+	if model.tasks.Err != nil {
+		t.Fatalf("toggle: %v", model.tasks.Err)
+	}
+	if len(model.tasks.List.Items()) != 1 {
+		t.Fatalf("open tasks = %d, want 1 after toggling", len(model.tasks.List.Items()))
+	}
+	note, err := svc.LoadNote(filename)
+	if err != nil {
+		t.Fatalf("LoadNote: %v", err)
+	}
+	if !strings.Contains(note.Content, "- [x] call the bank") {
+		t.Fatalf("note not updated: %q", note.Content)
+	}
+}
+
+func TestEditorPreviewRendersLatestBody(t *testing.T) {
+	setupTestConfig(t)
+	model := NewAppModel()
+	model.config.StoragePath = t.TempDir()
+	model.width, model.height = 120, 40
+	model.startEditorForNew()
+	model.editor.Title.Blur()
+	model.editor.Body.Focus()
+	model.editor.Body.SetValue("# Heading\n\nfirst draft")
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	model = updated.(AppModel)
+	if !model.editor.Preview || model.editor.PreviewView.Width == 0 {
+		t.Fatalf("preview not enabled: %+v", model.editor.Preview)
+	}
+	stale := cmd()
+
+	model.editor.Body.SetValue("# Heading\n\nsecond draft")
+	model = applyCmd(model, model.renderEditorPreviewCmd())
+	updated, _ = model.Update(stale)
+	model = updated.(AppModel)
+	content := model.editor.PreviewView.View()
+	if !strings.Contains(content, "second") || strings.Contains(content, "first") {
+		t.Fatalf("preview = %q, want latest body only", content)
+	}
+	if !strings.Contains(model.View(), "Preview") {
+		t.Fatalf("editor view missing preview pane")
+	}
+}
+
+func TestEditorEscConfirmsUnsavedChanges(t *testing.T) {
+	setupTestConfig(t)
+	root := t.TempDir()
+	model := NewAppModel()
+	model.config.StoragePath = root
+	model.startEditorForNew()
+	model.editor.Title.Blur()
+	model.editor.Body.Focus()
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("draft")})
+	model = updated.(AppModel)
+	if !model.editor.AutosavePending || cmd == nil {
+		t.Fatalf("autosave not scheduled")
+	}
+	model = applyCmd(model, model.autosaveDraftCmd())
+	svc := journal.NewService(root)
+	if drafts, _, err := svc.ListDrafts(); err != nil || len(drafts) != 1 || drafts[0].Body != "draft" {
+		t.Fatalf("ListDrafts = %v, %v", drafts, err)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(AppModel)
+	if model.screen != screenEditor || model.editor.Confirm != confirmLeave {
+		t.Fatalf("esc with unsaved changes left the editor: screen=%v", model.screen)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(AppModel)
+	if model.screen != screenEditor || model.editor.Confirm != confirmNone {
+		t.Fatalf("esc did not cancel the prompt")
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(AppModel)
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	model = updated.(AppModel)
+	if model.screen != screenDashboard {
+		t.Fatalf("discard screen = %v, want dashboard", model.screen)
+	}
+	if drafts, _, _ := svc.ListDrafts(); len(drafts) != 0 {
+		t.Fatalf("draft kept after discard: %v", drafts)
+	}
+}
+
+func TestDashboardRecoversDraft(t *testing.T) {
+	setupTestConfig(t)
+	root := t.TempDir()
+	svc := journal.NewService(root)
+	created := time.Date(2024, 5, 4, 8, 0, 0, 0, time.Local)
+	if err := svc.SaveDraft(journal.Draft{Title: "Lost", Body: "unsaved words", Created: created}); err != nil {
+		t.Fatalf("SaveDraft: %v", err)
+	}
+
+	model := NewAppModel()
+	model.config.StoragePath = root
+	model.screen = screenDashboard
+	model = applyCmd(model, model.loadDraftsCmd())
+	if len(model.dashboard.Drafts) != 1 || !strings.Contains(model.View(), "Recover unsaved draft") {
+		t.Fatalf("recovery prompt not shown")
+	}
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(AppModel)
+	if model.screen != screenEditor || model.editor.Body.Value() != "unsaved words" || !model.editor.Created.Equal(created) {
+		t.Fatalf("draft not recovered: screen=%v body=%q", model.screen, model.editor.Body.Value())
+	}
+	if !model.editorDirty() {
+		t.Fatalf("recovered draft should count as unsaved")
+	}
+
+	model = applyCmd(model.saveEditorNote())
+	if notes, _ := svc.ListNotes(); len(notes) != 1 {
+		t.Fatalf("notes after save = %v", notes)
+	}
+	if drafts, _, _ := svc.ListDrafts(); len(drafts) != 0 {
+		t.Fatalf("draft kept after save: %v", drafts)
+	}
+}
+
+func TestConfiguredKeysDriveDispatchAndHelp(t *testing.T) {
+	setupTestConfig(t)
+	root, _ := createTestJournal(t)
+	if err := config.NewConf(root, "", "", false).SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	confPath, _ := config.BuildConfPath(config.Home, config.XdgConfigHome)
+	f, err := os.OpenFile(filepath.Join(confPath, config.ConfFileName), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("open conf: %v", err)
+	}
+	f.WriteString("\n[Keys]\npreset = vim\ntasks = ctrl+t\ndiscard = d\n")
+	f.Close()
+
+	model := NewAppModel()
+	if model.screen != screenDashboard {
+		t.Fatalf("screen = %v, want dashboard", model.screen)
+	}
+	if help := model.helpText(); !strings.Contains(help, "ctrl+t tasks") {
+		t.Fatalf("help text not generated from keymap: %q", help)
+	}
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	model = updated.(AppModel)
+	if !model.showHelp || !strings.Contains(model.View(), "Calendar") {
+		t.Fatalf("help overlay not shown")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	model = updated.(AppModel)
+	if model.showHelp || model.screen != screenDashboard {
+		t.Fatalf("overlay should close without acting: help=%v screen=%v", model.showHelp, model.screen)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	model = updated.(AppModel)
+	if model.screen != screenDashboard {
+		t.Fatalf("remapped key still opens tasks")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	model = updated.(AppModel)
+	if model.screen != screenTasks {
+		t.Fatalf("ctrl+t screen = %v, want tasks", model.screen)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	model = updated.(AppModel)
+	if model.screen != screenDashboard {
+		t.Fatalf("vim q should go back, screen = %v", model.screen)
+	}
+
+	model.startEditorForNew()
+	model.editor.Title.Blur()
+	model.editor.Body.Focus()
+	for _, r := range "qd" {
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(AppModel)
+	}
+	if model.screen != screenEditor || model.editor.Confirm != confirmNone || model.editor.Body.Value() != "qd" {
+		t.Fatalf("q should be typed in the editor: screen=%v confirm=%v body=%q", model.screen, model.editor.Confirm, model.editor.Body.Value())
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(AppModel)
+	if model.editor.Confirm != confirmLeave {
+		t.Fatalf("esc should still ask about unsaved changes")
+	}
+	if help := model.helpText(); !strings.Contains(help, "ctrl+s save") || !strings.Contains(help, "d discard") {
+		t.Fatalf("unsaved changes help not from keymap: %q", help)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	model = updated.(AppModel)
+	if model.screen != screenDashboard {
+		t.Fatalf("configured discard key did not leave the editor, screen = %v", model.screen)
+	}
+
+	model.screen = screenSettings
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	model = updated.(AppModel)
+	if model.screen != screenSettings {
+		t.Fatalf("q in the settings form should not leave it, screen = %v", model.screen)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}, model.keys.Back) {
+		t.Fatalf("vim q binding lost after typing")
+	}
+}
+
+func TestPaletteRunsFuzzyMatchedCommand(t *testing.T) {
+	setupTestConfig(t)
+	root, _ := createTestJournal(t)
+	if err := config.NewConf(root, "", "", false).SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+
+	model := NewAppModel()
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	model = updated.(AppModel)
+	if !model.palette.Open || !strings.Contains(model.View(), "Toggle theme") {
+		t.Fatalf("palette not shown")
+	}
+	for _, r := range "cal" {
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(AppModel)
+	}
+	if len(model.palette.Matches) == 0 {
+		t.Fatalf("no matches for %q", model.palette.Input.Value())
+	}
+	if title := model.palette.Commands[model.palette.Matches[0].Index].Title; title != "Calendar" {
+		t.Fatalf("best match = %q, want Calendar", title)
+	}
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = applyCmd(updated.(AppModel), cmd)
+	if model.palette.Open || model.screen != screenCalendar {
+		t.Fatalf("palette open=%v screen=%v, want calendar", model.palette.Open, model.screen)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	model = updated.(AppModel)
+	for _, r := range "theme" {
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(AppModel)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(AppModel)
+	if model.config.Theme == "auto" || !strings.HasPrefix(model.dashboard.Status, "Theme: ") {
+		t.Fatalf("theme = %q status = %q", model.config.Theme, model.dashboard.Status)
+	}
+}
+
+func TestPaletteSwitchesJournal(t *testing.T) {
+	setupTestConfig(t)
+	root, _ := createTestJournal(t)
+	if err := config.NewConf(root, "", "", false).SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	other := t.TempDir()
+	if _, err := journal.NewService(other).SaveNote("Other Journal", "elsewhere", time.Now()); err != nil {
+		t.Fatalf("SaveNote: %v", err)
+	}
+
+	model := NewAppModel()
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	model = updated.(AppModel)
+	for _, r := range "switch" {
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(AppModel)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(AppModel)
+	if model.palette.Pending == nil || model.palette.Input.Value() != root {
+		t.Fatalf("switch journal should ask for a folder, got %q", model.palette.Input.Value())
+	}
+	model.palette.Input.SetValue(other)
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(AppModel)
+	if model.config.StoragePath != other {
+		t.Fatalf("StoragePath = %q, want %q", model.config.StoragePath, other)
+	}
+	for _, msg := range cmd().(tea.BatchMsg) {
+		model = applyCmd(model, msg)
+	}
+	if len(model.dashboard.Notes) != 1 || model.dashboard.Notes[0].Title != "Other Journal" {
+		t.Fatalf("dashboard not reloaded: %+v", model.dashboard.Notes)
+	}
+	conf, err := config.LoadConf()
+	if err != nil || conf.JournalPath != other {
+		t.Fatalf("config not saved: %+v %v", conf, err)
+	}
+}
+
+func TestRecoveredDraftOfSavedNoteIsNotFlaggedAsChanged(t *testing.T) {
+	setupTestConfig(t)
+	root, filename := createTestJournal(t)
+	svc := journal.NewService(root)
+	if err := svc.SaveDraft(journal.Draft{File: filename, Title: "Test Journal", Body: "more words", Created: time.Now()}); err != nil {
+		t.Fatalf("SaveDraft: %v", err)
+	}
+	info, err := svc.StatNote(filename)
+	if err != nil {
+		t.Fatalf("StatNote: %v", err)
+	}
+
+	model := NewAppModel()
+	model.config.StoragePath = root
+	model.screen = screenDashboard
+	model = applyCmd(model, model.loadDraftsCmd())
+	model.recoverDraft(true)
+	model.warnEditorChanged(false, info)
+	if model.editor.DiskWarning != "" {
+		t.Fatalf("recovered draft flagged: %q", model.editor.DiskWarning)
+	}
+}
+
+func TestQuitWhileEditorSaves(t *testing.T) {
+	setupTestConfig(t)
+	root, _ := createTestJournal(t)
+	model := NewAppModel()
+	model.config.StoragePath = root
+	model.startEditorForNew()
+	model.editor.Body.SetValue("words")
+	model, save := model.saveEditorNote()
+	if !model.editor.Saving || save == nil {
+		t.Fatalf("save should run in the background")
+	}
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	model = updated.(AppModel)
+	if cmd != nil || model.editor.Body.Value() != "words" {
+		t.Fatalf("typing while saving changed the body to %q", model.editor.Body.Value())
+	}
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if cmd == nil {
+		t.Fatalf("ctrl+c while saving should quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatalf("ctrl+c while saving did not quit")
+	}
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/utils"
+)
+
+// droppedFilePath recognises a file dropped onto the terminal, which arrives
+// as a pasted path that may be quoted, shell-escaped or a file:// URL.
+func droppedFilePath(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.Contains(text, "\n") {
+		return ""
+	}
+	text = strings.Trim(text, `"'`)
+	if strings.HasPrefix(text, "file://") {
+		if parsed, err := url.Parse(text); err == nil {
+			text = parsed.Path
+		}
+	}
+	text = strings.ReplaceAll(text, `\ `, " ")
+	if text == "" || !filepath.IsAbs(text) {
+		return ""
+	}
+	info, err := os.Stat(text)
+	if err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+	return text
+}
+
+// attachToEditor stores the file and inserts a link at the cursor. New notes
+// have no attachment folder yet, so their files are linked through a
+// placeholder that the save replaces.
+func (m *AppModel) attachToEditor(source string) {
+	if m.config.StoragePath == "" {
+		m.editor.Err = fmt.Errorf("journal path is not set")
+		return
+	}
+	if m.editor.File != "" {
+		service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
+		attachment, err := service.AddAttachment(m.editor.File, source)
+		if err != nil {
+			m.editor.Err = err
+			return
+		}
+		m.editor.Err = nil
+		m.editor.Body.InsertString(attachment.Link())
+		return
+	}
+
+	name := filepath.Base(source)
+	placeholder := journal.Attachment{
+		Name:      name,
+		Path:      path.Join(journal.AttachmentsDir, "pending", fmt.Sprint(len(m.editor.Pending)+1), name),
+		Encrypted: m.config.Encrypt,
+	}
+	m.editor.Pending = append(m.editor.Pending, journal.PendingAttachment{Source: source, Link: placeholder.URL()})
+	m.editor.Err = nil
+	m.editor.Body.InsertString(placeholder.Link())
+}
+
+func (m *AppModel) openAttachmentCmd(index int) tea.Cmd {
+	if index < 0 || index >= len(m.viewer.Attachments) {
+		return nil
+	}
+	attachment := m.viewer.Attachments[index]
+	if m.tempDir == "" {
+		dir, err := os.MkdirTemp("", "a7-attachments-")
+		if err != nil {
+			m.viewer.Status = fmt.Sprintf("Unable to open %s: %v", attachment.Name, err)
+			return nil
+		}
+		m.tempDir = dir
+	}
+	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
+	dir := m.tempDir
+	return func() tea.Msg {
+		target, err := service.ExtractAttachment(attachment, dir)
+		if err == nil {
+			err = utils.OpenWithSystem(target)
+		}
+		return attachmentOpenedMsg{name: attachment.Name, err: err}
+	}
+}
+
+// Cleanup removes decrypted attachment copies made while the app ran,
+// stops watching the journal and releases the journal lock.
+func (m AppModel) Cleanup() {
+	m.stopWatching()
+	m.releaseJournal()
+	if m.tempDir != "" {
+		os.RemoveAll(m.tempDir)
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/never00rei/a7/ui/components"
+	"github.com/never00rei/a7/ui/screens"
+)
+
+func (m *AppModel) openCalendar() {
+	now := time.Now()
+	m.calendar.Cursor = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	m.calendar.ListFocused = false
+	m.screen = screenCalendar
+	m.refreshCalendarDay()
+	*m = m.updateCalendarListSize()
+}
+
+func (m *AppModel) moveCalendarCursor(years, months, days int) {
+	cursor := m.calendar.Cursor
+	if months != 0 || years != 0 {
+		// Clamp to the last day so Jan 31 + 1 month lands on Feb 28/29.
+		first := time.Date(cursor.Year(), cursor.Month(), 1, 0, 0, 0, 0, cursor.Location()).AddDate(years, months, 0)
+		last := first.AddDate(0, 1, -1).Day()
+		day := cursor.Day()
+		if day > last {
+			day = last
+		}
+		cursor = time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, cursor.Location())
+	}
+	m.calendar.Cursor = cursor.AddDate(0, 0, days)
+	m.refreshCalendarDay()
+}
+
+func (m *AppModel) refreshCalendarDay() {
+	byDay := components.NotesByDay(m.dashboard.Notes)
+	m.calendar.DayList.SetItems(components.BuildNoteItems(byDay[components.DayKey(m.calendar.Cursor)]))
+	m.calendar.DayList.Select(0)
+}
+
+func (m AppModel) calendarDayCount() int {
+	return len(m.calendar.DayList.Items())
+}
+
+func (m AppModel) openCalendarDay() (AppModel, tea.Cmd) {
+	switch m.calendarDayCount() {
+	case 0:
+		return m, nil
+	case 1:
+		item, ok := m.calendar.DayList.Items()[0].(components.NoteItem)
+		if !ok {
+			return m, nil
+		}
+		return m.openViewerForNote(item.Info, screenCalendar)
+	default:
+		m.calendar.ListFocused = true
+		return m, nil
+	}
+}
+
+func (m AppModel) updateCalendarListSize() AppModel {
+	layout := m.layout()
+	_, rightWidth := layout.SplitPaneContentWidths(screens.CalendarLeftRatio)
+	height := layout.PaneContentHeight(layout.BodyHeight())
+	if height < 0 {
+		height = 0
+	}
+	m.calendar.DayList.SetSize(rightWidth, height)
+	return m
+}
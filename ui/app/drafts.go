@@ -0,0 +1,157 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/never00rei/a7/journal"
+)
+
+const editorAutosaveInterval = 5 * time.Second
+
+type editorConfirm int
+
+const (
+	confirmNone editorConfirm = iota
+	confirmLeave
+	confirmQuit
+)
+
+// markEditorClean records the editor's current text as the saved state, so
+// only later changes count as unsaved.
+func (m *AppModel) markEditorClean() {
+	m.editor.CleanTitle = m.editor.Title.Value()
+	m.editor.CleanBody = m.editor.Body.Value()
+	m.editor.DraftID = journal.DraftID(m.editor.File, m.editor.Created)
+	m.editor.Confirm = confirmNone
+}
+
+func (m AppModel) editorDirty() bool {
+	return m.editor.Title.Value() != m.editor.CleanTitle || m.editor.Body.Value() != m.editor.CleanBody
+}
+
+// scheduleAutosave starts the autosave timer unless one is already running,
+// so drafts are written periodically while typing rather than per keystroke.
+func (m *AppModel) scheduleAutosave() tea.Cmd {
+	if m.editor.AutosavePending || !m.editorDirty() {
+		return nil
+	}
+	m.editor.AutosavePending = true
+	return tea.Tick(editorAutosaveInterval, func(time.Time) tea.Msg {
+		return editorAutosaveMsg{}
+	})
+}
+
+func (m *AppModel) autosaveDraftCmd() tea.Cmd {
+	m.editor.AutosavePending = false
+	if m.screen != screenEditor || m.config.StoragePath == "" || !m.editorDirty() {
+		return nil
+	}
+	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
+	draft := journal.Draft{
+		ID:            m.editor.DraftID,
+		File:          m.editor.File,
+		OriginalTitle: m.editor.OriginalTitle,
+		Title:         m.editor.Title.Value(),
+		Body:          m.editor.Body.Value(),
+		Prompt:        m.editor.Prompt,
+		Created:       m.editor.Created,
+	}
+	return func() tea.Msg {
+		return draftSavedMsg{err: service.SaveDraft(draft)}
+	}
+}
+
+func (m *AppModel) discardEditorDraft() {
+	if m.config.StoragePath == "" || m.editor.DraftID == "" {
+		return
+	}
+	service := journal.NewService(m.config.StoragePath)
+	_ = service.DiscardDraft(m.editor.DraftID)
+}
+
+// confirmEditorExit handles the unsaved-changes prompt shown when leaving or
+// quitting the editor.
+func (m *AppModel) confirmEditorExit(msg tea.KeyMsg) tea.Cmd {
+	quit := m.editor.Confirm == confirmQuit
+	switch {
+	case key.Matches(msg, m.keys.Save):
+		m.editor.Confirm = confirmNone
+		updated, cmd := m.saveEditorNote()
+		*m = updated
+		m.editor.QuitOnSave = quit && cmd != nil
+		return cmd
+	case key.Matches(msg, m.keys.DiscardDraft):
+		m.discardEditorDraft()
+		m.markEditorClean()
+		if quit {
+			return tea.Quit
+		}
+		m.screen = screenDashboard
+		return nil
+	case key.Matches(msg, m.keys.Back):
+		m.editor.Confirm = confirmNone
+	}
+	return nil
+}
+
+func (m AppModel) loadDraftsCmd() tea.Cmd {
+	if m.config.StoragePath == "" {
+		return nil
+	}
+	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
+	return func() tea.Msg {
+		drafts, unreadable, err := service.ListDrafts()
+		return draftsMsg{drafts: drafts, unreadable: unreadable, err: err}
+	}
+}
+
+func (m *AppModel) startEditorForDraft(draft journal.Draft) {
+	m.startEditorForNew()
+	m.editor.File = draft.File
+	m.editor.OriginalTitle = draft.OriginalTitle
+	m.editor.Created = draft.Created
+	m.editor.Prompt = draft.Prompt
+	m.editor.Title.SetValue(draft.Title)
+	m.editor.Body.SetValue(draft.Body)
+	m.editor.DraftID = draft.ID
+	if draft.File != "" {
+		if info, err := journal.NewService(m.config.StoragePath).StatNote(draft.File); err == nil {
+			m.editor.ModTime = info.ModTime
+		}
+	}
+	m.editor.CleanTitle = ""
+	m.editor.CleanBody = ""
+}
+
+// recoverDraft opens or discards the first draft waiting for recovery.
+func (m *AppModel) recoverDraft(open bool) {
+	if len(m.dashboard.Drafts) == 0 {
+		return
+	}
+	draft := m.dashboard.Drafts[0]
+	m.dashboard.Drafts = m.dashboard.Drafts[1:]
+	if open {
+		m.startEditorForDraft(draft)
+		return
+	}
+	service := journal.NewService(m.config.StoragePath)
+	if err := service.DiscardDraft(draft.ID); err != nil {
+		m.dashboard.Status = fmt.Sprintf("Unable to discard draft: %v", err)
+	}
+}
+
+func formatDraftPrompt(drafts []journal.Draft) string {
+	draft := drafts[0]
+	title := draft.Title
+	if title == "" {
+		title = "Untitled"
+	}
+	prompt := fmt.Sprintf("Recover unsaved draft %q from %s?", title, draft.Saved.Local().Format("Mon 2 Jan 15:04"))
+	if len(drafts) > 1 {
+		prompt += fmt.Sprintf(" (%d more)", len(drafts)-1)
+	}
+	return prompt
+}
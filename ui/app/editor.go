@@ -8,13 +8,22 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/hooks"
+	"github.com/never00rei/a7/journal/prompts"
+	"github.com/never00rei/a7/journal/templates"
 	"github.com/never00rei/a7/ui/components"
 	"github.com/never00rei/a7/ui/layout"
+	"github.com/never00rei/a7/ui/screens"
 )
 
 func (m *AppModel) startEditorForNew() {
 	m.editor.File = ""
+	m.editor.OriginalTitle = ""
 	m.editor.Created = time.Now()
+	m.editor.Prompt = ""
+	m.editor.Pending = nil
+	m.editor.ModTime = time.Time{}
+	m.editor.DiskWarning = ""
 	m.editor.Err = nil
 	m.editor.Title.SetValue("")
 	m.editor.Body.SetValue("")
@@ -22,25 +31,61 @@ func (m *AppModel) startEditorForNew() {
 	m.editor.Body.Blur()
 	m.screen = screenEditor
 	m.updateEditorSize()
+	m.markEditorClean()
 }
 
-func (m *AppModel) startEditorForSelected() {
+func (m *AppModel) startEditorForTemplate(tmpl templates.Template) {
+	m.startEditorForNew()
+	vars := templates.Vars{
+		Now:     m.editor.Created,
+		Journal: m.journalName(),
+	}
+	if tmpl.UsesPrompt() {
+		if available, err := loadPrompts(); err == nil {
+			if prompt, err := m.pickPrompt(available, prompts.AnyCategory); err == nil {
+				vars.Prompt = prompt.Text
+				m.editor.Prompt = prompt.Text
+			}
+		}
+	}
+	title, body := tmpl.Render(vars)
+	m.editor.Title.SetValue(title)
+	m.editor.Body.SetValue(body)
+	m.markEditorClean()
+}
+
+func (m *AppModel) startEditorForPrompt(prompt prompts.Prompt) {
+	m.startEditorForNew()
+	m.editor.Prompt = prompt.Text
+	m.editor.Title.SetValue(prompt.Text)
+	m.editor.Body.SetValue("> " + prompt.Text + "\n\n")
+	m.editor.Title.Blur()
+	m.editor.Body.Focus()
+	m.markEditorClean()
+}
+
+func (m *AppModel) startEditorForSelected() tea.Cmd {
 	if m.config.StoragePath == "" {
-		return
+		return nil
 	}
 	item := m.dashboard.List.SelectedItem()
 	noteItem, ok := item.(components.NoteItem)
 	if !ok {
-		return
+		return nil
 	}
-	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
-	note, err := service.LoadNote(noteItem.Info.Filename)
+	var deferred []hooks.Call
+	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath), journal.WithHooks(hookRunner(&deferred)))
+	note, err := service.OpenNote(noteItem.Info.Filename)
 	if err != nil {
 		m.editor.Err = err
-		return
+		return nil
 	}
 
 	m.editor.File = noteItem.Info.Filename
+	m.editor.OriginalTitle = note.Title
+	m.editor.ModTime = note.ModTime
+	m.editor.DiskWarning = ""
+	m.editor.Pending = nil
 	m.editor.Created = note.Created
 	if m.editor.Created.IsZero() {
 		if created, ok := components.ParseFilenameTimestamp(noteItem.Info.Filename); ok {
@@ -54,15 +99,20 @@ func (m *AppModel) startEditorForSelected() {
 	m.editor.Body.Blur()
 	m.screen = screenEditor
 	m.updateEditorSize()
+	m.markEditorClean()
+	return runHooksCmd(deferred)
 }
 
-func (m *AppModel) startEditorForViewer() {
+func (m *AppModel) startEditorForViewer() tea.Cmd {
 	if m.viewer.Note == nil {
-		m.startEditorForSelected()
-		return
+		return m.startEditorForSelected()
 	}
 	note := m.viewer.Note
 	m.editor.File = note.Filename
+	m.editor.OriginalTitle = note.Title
+	m.editor.ModTime = note.ModTime
+	m.editor.DiskWarning = ""
+	m.editor.Pending = nil
 	m.editor.Created = note.Created
 	m.editor.Err = nil
 	m.editor.Title.SetValue(note.Title)
@@ -71,6 +121,8 @@ func (m *AppModel) startEditorForViewer() {
 	m.editor.Body.Blur()
 	m.screen = screenEditor
 	m.updateEditorSize()
+	m.markEditorClean()
+	return nil
 }
 
 func (m *AppModel) updateEditorSize() *AppModel {
@@ -83,6 +135,10 @@ func (m *AppModel) updateEditorSize() *AppModel {
 
 	m.editor.Title.Width = width
 	bodyWidth := width - 4
+	previewWidth := 0
+	if m.editor.Preview {
+		bodyWidth, previewWidth = layout.SplitPaneContentWidthsForTotal(paneWidth, screens.EditorPreviewRatio)
+	}
 	if bodyWidth < 0 {
 		bodyWidth = 0
 	}
@@ -95,6 +151,8 @@ func (m *AppModel) updateEditorSize() *AppModel {
 	}
 	bodyContentHeight := layout.PaneContentHeight(bodyPaneHeight)
 	m.editor.Body.SetHeight(bodyContentHeight)
+	m.editor.PreviewView.Width = previewWidth
+	m.editor.PreviewView.Height = bodyContentHeight
 	return m
 }
 
@@ -109,34 +167,59 @@ func (m AppModel) editorPaneHeights(layout layout.Layout) (int, int) {
 	return titleHeight, bodyPaneHeight
 }
 
+// saveEditorNote saves the editor in the background, since pre-save hooks
+// may take a while. The editor ignores keys until editorSavedMsg arrives.
 func (m AppModel) saveEditorNote() (AppModel, tea.Cmd) {
 	if m.config.StoragePath == "" {
 		m.editor.Err = fmt.Errorf("journal path is not set")
 		return m, nil
 	}
+	if m.editor.Saving {
+		return m, nil
+	}
 
 	title := strings.TrimSpace(m.editor.Title.Value())
 	if title == "" {
 		title = "Untitled"
 	}
-	body := m.editor.Body.Value()
+	edit := journal.Edit{
+		Filename:    m.editor.File,
+		Title:       title,
+		Body:        m.editor.Body.Value(),
+		Created:     m.editor.Created,
+		Rename:      m.editor.File != "" && title != m.editor.OriginalTitle,
+		Attachments: m.editor.Pending,
+	}
+	if m.editor.File == "" && m.editor.Prompt != "" {
+		edit.Options = append(edit.Options, journal.WithPrompt(m.editor.Prompt))
+	}
+	path, encrypt, keyPath := m.config.StoragePath, m.config.Encrypt, m.config.SshKeyPath
+	m.editor.Saving = true
+	return m, func() tea.Msg {
+		var deferred []hooks.Call
+		service := journal.NewService(path, journal.WithEncryption(encrypt, keyPath), journal.WithHooks(hookRunner(&deferred)))
+		_, _, err := service.SaveEdit(edit)
+		return editorSavedMsg{hooks: deferred, err: err}
+	}
+}
 
-	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
-	if m.editor.File == "" {
-		_, err := service.SaveNote(title, body, m.editor.Created)
-		if err != nil {
-			m.editor.Err = err
-			return m, nil
-		}
-	} else {
-		if err := service.UpdateNote(m.editor.File, title, body, m.editor.Created); err != nil {
-			m.editor.Err = err
-			return m, nil
-		}
+func (m AppModel) applyEditorSaved(msg editorSavedMsg) (AppModel, tea.Cmd) {
+	m.editor.Saving = false
+	quit := m.editor.QuitOnSave
+	m.editor.QuitOnSave = false
+	if msg.err != nil {
+		m.editor.Err = msg.err
+		return m, nil
 	}
 
+	m.discardEditorDraft()
+	m.markEditorClean()
 	m.editor.Err = nil
+	m.editor.Pending = nil
+	if quit {
+		return m, tea.Sequence(runHooksCmd(msg.hooks), tea.Quit)
+	}
 	m.screen = screenDashboard
 	m = m.resetDashboardNotes()
-	return m, m.loadDashboardNotesCmd()
+	return m, tea.Batch(m.loadDashboardNotesCmd(), runHooksCmd(msg.hooks))
 }
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const editorPreviewDelay = 250 * time.Millisecond
+
+func (m *AppModel) toggleEditorPreview() tea.Cmd {
+	m.editor.Preview = !m.editor.Preview
+	m.updateEditorSize()
+	if !m.editor.Preview {
+		return nil
+	}
+	return m.renderEditorPreviewCmd()
+}
+
+// scheduleEditorPreview debounces re-rendering while typing: only the tick
+// for the latest edit triggers a render.
+func (m *AppModel) scheduleEditorPreview() tea.Cmd {
+	if !m.editor.Preview {
+		return nil
+	}
+	m.editor.PreviewSeq++
+	seq := m.editor.PreviewSeq
+	return tea.Tick(editorPreviewDelay, func(time.Time) tea.Msg {
+		return editorPreviewTickMsg{seq: seq}
+	})
+}
+
+func (m *AppModel) renderEditorPreviewCmd() tea.Cmd {
+	m.editor.PreviewSeq++
+	seq := m.editor.PreviewSeq
+	width := m.editor.PreviewView.Width
+	body := m.editor.Body.Value()
+	return func() tea.Msg {
+		if body == "" {
+			return editorPreviewMsg{seq: seq, rendered: "Nothing to preview yet."}
+		}
+		rendered, err := renderMarkdown(width, body)
+		if err != nil {
+			rendered = body
+		}
+		return editorPreviewMsg{seq: seq, rendered: rendered}
+	}
+}
+
+func (m *AppModel) applyEditorPreview(msg editorPreviewMsg) {
+	if msg.seq != m.editor.PreviewSeq {
+		return
+	}
+	m.editor.PreviewView.SetContent(msg.rendered)
+	m.syncEditorPreviewScroll()
+}
+
+// syncEditorPreviewScroll keeps the preview roughly level with the cursor by
+// scrolling it to the same relative position.
+func (m *AppModel) syncEditorPreviewScroll() {
+	lines := m.editor.Body.LineCount()
+	if lines <= 1 {
+		m.editor.PreviewView.GotoTop()
+		return
+	}
+	scrollable := m.editor.PreviewView.TotalLineCount() - m.editor.PreviewView.Height
+	if scrollable <= 0 {
+		m.editor.PreviewView.GotoTop()
+		return
+	}
+	ratio := float64(m.editor.Body.Line()) / float64(lines-1)
+	m.editor.PreviewView.SetYOffset(int(ratio * float64(scrollable)))
+}
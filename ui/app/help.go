@@ -0,0 +1,146 @@
+package app
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/never00rei/a7/ui/keys"
+)
+
+// hint is a fixed footer entry for keys that are not remappable, such as the
+// list filter or the walkthrough's skip.
+func hint(keyLabel, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(keyLabel), key.WithHelp(keyLabel, desc))
+}
+
+func (m AppModel) helpText() string {
+	k := m.keys
+	d := keys.Describe
+	filter := m.dashboard.List.KeyMap.Filter
+	switch m.screen {
+	case screenWelcome:
+		return keys.HelpLine(d(k.Open, "begin"), k.Quit)
+	case screenWalkthroughStorage:
+		return keys.HelpLine(d(k.Open, "next"), d(k.PrevField, "back"), k.Quit)
+	case screenWalkthroughPrivacy:
+		return keys.HelpLine(d(k.Open, "next"), d(k.PrevField, "back"), hint("s", "skip"), k.Quit)
+	case screenDashboard:
+		if len(m.dashboard.Drafts) > 0 {
+			return keys.HelpLine(d(k.Open, "recover draft"), k.DiscardDraft, d(k.Back, "later"), k.Quit)
+		}
+		return keys.HelpLine(k.Up, k.Down, filter, d(k.Open, "view"), k.New, k.Prompt, k.Calendar, k.Stats, k.Tasks,
+			k.Sort, k.Reverse, k.Group, k.Edit, k.Settings, k.Palette, k.Help, k.Quit)
+	case screenViewer:
+		if m.viewer.Finding {
+			return m.viewer.Find.View() + " • " + keys.HelpLine(hint("enter", "keep matches"), hint("esc", "clear"))
+		}
+		if m.viewer.OutlineOpen {
+			return keys.HelpLine(k.Up, k.Down, filter, d(k.Open, "jump"), d(k.Back, "close"), k.Quit)
+		}
+		bindings := []key.Binding{k.NextEntry, k.PrevEntry, k.Find}
+		if len(m.viewer.Matches) > 0 {
+			bindings = append(bindings, k.NextMatch, k.PrevMatch)
+		}
+		bindings = append(bindings, k.Outline)
+		if len(m.viewerLinkTargets()) > 0 {
+			bindings = append(bindings, k.NextLink, d(k.Open, "follow"))
+		}
+		if len(m.viewer.Attachments) > 0 {
+			bindings = append(bindings, k.Attachment)
+		}
+		bindings = append(bindings, k.Back, k.Edit, k.Palette, k.Help, k.Quit)
+		if status := m.viewerFindStatus(); status != "" && m.viewer.Status == "" {
+			return status + " • " + keys.HelpLine(bindings...)
+		}
+		if m.viewer.Status != "" {
+			return m.viewer.Status + " • " + keys.HelpLine(bindings...)
+		}
+		return keys.HelpLine(bindings...)
+	case screenEditor:
+		if m.editor.Saving {
+			return "Saving..."
+		}
+		if m.editor.Confirm != confirmNone {
+			return "Unsaved changes • " + keys.HelpLine(d(k.Save, "save"), d(k.DiscardDraft, "discard"), d(k.Back, "keep editing"))
+		}
+		if m.editor.DiskWarning != "" {
+			return m.editor.DiskWarning + " • " + keys.HelpLine(k.Save, k.Back, k.Quit)
+		}
+		return keys.HelpLine(k.NextField, k.Save, k.Preview, k.Back, k.Palette, k.Quit)
+	case screenSettings:
+		return keys.HelpLine(hint("tab", "next"), d(k.PrevField, "back"), k.Back, k.Quit)
+	case screenTemplatePicker:
+		return keys.HelpLine(k.Up, k.Down, filter, d(k.Open, "use template"), k.Back, k.Palette, k.Help, k.Quit)
+	case screenPromptPicker:
+		return keys.HelpLine(k.Up, k.Down, filter, d(k.Open, "pick prompt"), k.Back, k.Palette, k.Help, k.Quit)
+	case screenStats:
+		return keys.HelpLine(k.Up, k.Down, k.Back, k.Palette, k.Help, k.Quit)
+	case screenTasks:
+		return keys.HelpLine(k.Up, k.Down, filter, k.ToggleTask, k.ShowDone, d(k.Open, "open note"), k.Back, k.Palette, k.Help, k.Quit)
+	case screenCalendar:
+		if m.calendar.ListFocused {
+			return keys.HelpLine(k.Up, k.Down, d(k.Open, "view"), d(k.Back, "calendar"), k.Quit)
+		}
+		return keys.HelpLine(d(k.Left, "prev day"), d(k.Right, "next day"), d(k.Up, "prev week"), d(k.Down, "next week"),
+			k.PrevMonth, k.NextMonth, k.PrevYear, k.NextYear, k.Today, d(k.Open, "open"), k.DayList, k.Back, k.Palette, k.Help, k.Quit)
+	default:
+		return keys.HelpLine(d(k.Open, "continue"), d(k.PrevField, "back"), k.Quit)
+	}
+}
+
+// helpOverlayAvailable reports whether ? should open the key overlay rather
+// than reach the screen, which is not the case while typing.
+func (m AppModel) helpOverlayAvailable() bool {
+	switch m.screen {
+	case screenDashboard:
+		return len(m.dashboard.Drafts) == 0 && m.dashboard.List.FilterState() != list.Filtering
+	case screenTemplatePicker:
+		return m.templatePicker.List.FilterState() != list.Filtering
+	case screenPromptPicker:
+		return m.promptPicker.List.FilterState() != list.Filtering
+	case screenTasks:
+		return m.tasks.List.FilterState() != list.Filtering
+	case screenViewer:
+		return !m.viewer.Finding && !(m.viewer.OutlineOpen && m.viewer.Outline.FilterState() == list.Filtering)
+	case screenCalendar, screenStats:
+		return true
+	default:
+		return false
+	}
+}
+
+// typing reports whether key presses are going into a text field, where
+// single character bindings have to be typed rather than acted on.
+func (m AppModel) typing() bool {
+	if m.palette.Open {
+		return true
+	}
+	switch m.screen {
+	case screenWalkthroughStorage, screenWalkthroughPrivacy, screenSetup, screenSettings:
+		return true
+	case screenEditor:
+		return m.editor.Confirm == confirmNone
+	case screenDashboard:
+		return m.dashboard.List.FilterState() == list.Filtering
+	case screenTemplatePicker:
+		return m.templatePicker.List.FilterState() == list.Filtering
+	case screenPromptPicker:
+		return m.promptPicker.List.FilterState() == list.Filtering
+	case screenTasks:
+		return m.tasks.List.FilterState() == list.Filtering
+	case screenViewer:
+		return m.viewer.Finding || (m.viewer.OutlineOpen && m.viewer.Outline.FilterState() == list.Filtering)
+	default:
+		return false
+	}
+}
+
+func (m *AppModel) applyKeys() {
+	m.keys.ApplyToList(&m.dashboard.List)
+	m.keys.ApplyToList(&m.templatePicker.List)
+	m.keys.ApplyToList(&m.promptPicker.List)
+	m.keys.ApplyToList(&m.calendar.DayList)
+	m.keys.ApplyToList(&m.tasks.List)
+	m.keys.ApplyToList(&m.viewer.Outline)
+	m.keys.ApplyToViewport(&m.viewer.Viewport)
+	m.keys.ApplyToViewport(&m.stats.Viewport)
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/never00rei/a7/config"
+	"github.com/never00rei/a7/journal/hooks"
+)
+
+// hookFailures collects failures of hooks that run after a save or open,
+// which do not stop the change, so the screen can mention them.
+type hookFailures []error
+
+// hookRunner returns a runner for the hooks folder in the config dir. The
+// hooks that run after a save or open are added to deferred instead, for
+// runHooksCmd, so a slow script never holds up the screen. Each save or
+// open gets its own runner.
+func hookRunner(deferred *[]hooks.Call) *hooks.Runner {
+	dir, err := config.BuildHooksPath(config.Home, config.XdgConfigHome)
+	if err != nil {
+		return nil
+	}
+	runner := hooks.NewRunner(dir)
+	if deferred != nil {
+		runner.Defer = func(call hooks.Call) {
+			*deferred = append(*deferred, call)
+		}
+	}
+	return runner
+}
+
+// runHooksCmd runs deferred hooks in the background.
+func runHooksCmd(calls []hooks.Call) tea.Cmd {
+	if len(calls) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		runner := hookRunner(nil)
+		if runner == nil {
+			return nil
+		}
+		var failed hookFailures
+		for _, call := range calls {
+			failed = append(failed, runner.Run(call)...)
+		}
+		if len(failed) == 0 {
+			return nil
+		}
+		return hooksFailedMsg{failures: failed}
+	}
+}
+
+func (f hookFailures) status() string {
+	switch len(f) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("Hook failed: %v", f[0])
+	default:
+		return fmt.Sprintf("%d hooks failed, first: %v", len(f), f[0])
+	}
+}
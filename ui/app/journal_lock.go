@@ -0,0 +1,92 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/lock"
+)
+
+// claimJournalCmd takes the lock for the journal being shown in the
+// background, releasing the one held for a previous journal. When another
+// a7 already has the journal open it is shared: the dashboard says so and
+// every save still takes its own note lock.
+func (m *AppModel) claimJournalCmd() tea.Cmd {
+	path := m.config.StoragePath
+	if m.journalLock != nil && sameJournal(filepath.Dir(m.journalLock.Path), path) {
+		return nil
+	}
+	if m.claiming != "" && sameJournal(m.claiming, path) {
+		return nil
+	}
+	previous := m.journalLock
+	m.journalLock = nil
+	m.sharedWith = nil
+	m.claiming = path
+	if path == "" && previous == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		previous.Release()
+		if path == "" {
+			return nil
+		}
+		held, err := journal.NewService(path).LockJournal()
+		return journalClaimedMsg{path: path, lock: held, err: err}
+	}
+}
+
+func (m *AppModel) applyJournalClaimed(msg journalClaimedMsg) tea.Cmd {
+	stale := msg.path != m.claiming
+	if !stale {
+		m.claiming = ""
+	}
+	if stale || !sameJournal(msg.path, m.config.StoragePath) {
+		return releaseJournalCmd(msg.lock)
+	}
+	var heldErr *lock.HeldError
+	switch {
+	case errors.As(msg.err, &heldErr):
+		m.sharedWith = &heldErr.Info
+		m.dashboard.Status = fmt.Sprintf("This journal is also open in another a7 (%s); entries are locked while they are saved", heldErr.Info)
+	case msg.err != nil:
+		m.dashboard.Status = fmt.Sprintf("Unable to lock journal: %v", msg.err)
+	default:
+		m.journalLock = msg.lock
+	}
+	m.dashboard.List.Title = m.dashboardListTitle()
+	return nil
+}
+
+func releaseJournalCmd(held *lock.Lock) tea.Cmd {
+	if held == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		held.Release()
+		return nil
+	}
+}
+
+func (m *AppModel) releaseJournal() {
+	m.journalLock.Release()
+	m.journalLock = nil
+	m.sharedWith = nil
+}
+
+// sameJournal reports whether two journal paths name the same folder, so
+// "~/j" and "~/j/" share one lock.
+func sameJournal(a, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
@@ -1,6 +1,10 @@
 package app
 
-import "github.com/never00rei/a7/journal"
+import (
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/hooks"
+	"github.com/never00rei/a7/journal/lock"
+)
 
 type errMsg struct {
 	err error
@@ -8,8 +12,93 @@ type errMsg struct {
 
 type configSavedMsg struct{}
 
+type editorPreviewTickMsg struct {
+	seq int
+}
+
+type editorPreviewMsg struct {
+	seq      int
+	rendered string
+}
+
+type attachmentOpenedMsg struct {
+	name string
+	err  error
+}
+
+type migrationMsg struct {
+	report journal.MigrationReport
+	err    error
+}
+
 type dashboardNotesMsg struct {
 	path  string
 	notes []journal.NoteInfo
 	err   error
 }
+
+type editorAutosaveMsg struct{}
+
+type editorSavedMsg struct {
+	hooks []hooks.Call
+	err   error
+}
+
+type taskToggledMsg struct {
+	hooks []hooks.Call
+	err   error
+}
+
+type hooksFailedMsg struct {
+	failures hookFailures
+}
+
+type draftSavedMsg struct {
+	err error
+}
+
+type draftsMsg struct {
+	drafts     []journal.Draft
+	unreadable []string
+	err        error
+}
+
+type statusMsg struct {
+	text string
+}
+
+type rekeyMsg struct {
+	keyPath string
+	report  journal.RekeyReport
+	err     error
+}
+
+type selectionLoadedMsg struct {
+	seq    int
+	info   journal.NoteInfo
+	loaded loadedNote
+	err    error
+}
+
+type backlinksMsg struct {
+	filename  string
+	backlinks []journal.NoteInfo
+}
+
+type watchStartedMsg struct {
+	path    string
+	watcher *journal.Watcher
+	err     error
+}
+
+type journalClaimedMsg struct {
+	path string
+	lock *lock.Lock
+	err  error
+}
+
+type journalChangedMsg struct {
+	watcher *journal.Watcher
+	changes []journal.Change
+	err     error
+}
@@ -0,0 +1,26 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/never00rei/a7/journal"
+)
+
+func (m AppModel) migrateLegacyCmd() tea.Cmd {
+	path := m.config.StoragePath
+	return func() tea.Msg {
+		report, err := journal.NewService(path).MigrateLegacy(false)
+		return migrationMsg{report: report, err: err}
+	}
+}
+
+func formatMigrationStatus(msg migrationMsg) string {
+	if msg.err != nil {
+		return fmt.Sprintf("Migration failed: %v", msg.err)
+	}
+	if len(msg.report.Migrated) == 0 {
+		return fmt.Sprintf("Scanned %d notes, none use the legacy format.", msg.report.Scanned)
+	}
+	return fmt.Sprintf("Migrated %d of %d notes. Originals are in %s.", len(msg.report.Migrated), msg.report.Scanned, msg.report.BackupDir)
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -9,6 +10,10 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/huh"
 	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/prompts"
+	"github.com/never00rei/a7/journal/stats"
+	"github.com/never00rei/a7/ui/components"
+	"github.com/sahilm/fuzzy"
 )
 
 type ConfigState struct {
@@ -16,6 +21,10 @@ type ConfigState struct {
 	SshKeyPath    string
 	SshPubKeyPath string
 	Encrypt       bool
+	SortBy        journal.SortField
+	SortDesc      bool
+	GroupBy       components.GroupMode
+	Theme         string
 }
 
 type WelcomeModel struct{}
@@ -35,26 +44,100 @@ type SettingsModel struct {
 }
 
 type DashboardModel struct {
-	List             list.Model
-	Notes            []journal.NoteInfo
-	Err              error
-	SelectedNote     *journal.Note
-	SelectedErr      error
-	SelectedFilename string
-	SelectedModTime  time.Time
+	List                list.Model
+	Notes               []journal.NoteInfo
+	Err                 error
+	SelectedNote        *journal.Note
+	SelectedErr         error
+	SelectedFilename    string
+	SelectedModTime     time.Time
+	SelectedLoading     bool
+	SelectedAttachments int
+	SelectionSeq        int
+	SelectionCancel     context.CancelFunc
+	Status              string
+	Drafts              []journal.Draft
 }
 
-type ViewerModel struct {
+type TemplatePickerModel struct {
+	List list.Model
+	Err  error
+}
+
+type PromptPickerModel struct {
+	List    list.Model
+	Prompts []prompts.Prompt
+	Err     error
+}
+
+type CalendarModel struct {
+	Cursor      time.Time
+	DayList     list.Model
+	ListFocused bool
+}
+
+type TasksModel struct {
+	List     list.Model
+	Tasks    []journal.Task
+	ShowDone bool
+	Err      error
+}
+
+type StatsModel struct {
 	Viewport viewport.Model
-	Title    string
-	Note     *journal.Note
-	Raw      string
+	Summary  stats.Summary
+}
+
+type ViewerModel struct {
+	Viewport    viewport.Model
+	Title       string
+	Note        *journal.Note
+	Raw         string
+	Back        screenID
+	Attachments []journal.Attachment
+	Links       []journal.NoteInfo
+	Backlinks   []journal.NoteInfo
+	LinkIndex   int
+	History     []journal.NoteInfo
+	Status      string
+	Rendered    string
+	Find        textinput.Model
+	Finding     bool
+	Query       string
+	Matches     []int
+	MatchIndex  int
+	Outline     list.Model
+	OutlineOpen bool
+}
+
+type PaletteModel struct {
+	Open     bool
+	Input    textinput.Model
+	Commands []paletteCommand
+	Matches  []fuzzy.Match
+	Cursor   int
+	Pending  *paletteCommand
 }
 
 type EditorModel struct {
-	Title   textinput.Model
-	Body    textarea.Model
-	Created time.Time
-	File    string
-	Err     error
+	Title           textinput.Model
+	Body            textarea.Model
+	Created         time.Time
+	File            string
+	OriginalTitle   string
+	Prompt          string
+	Pending         []journal.PendingAttachment
+	Preview         bool
+	PreviewView     viewport.Model
+	PreviewSeq      int
+	DraftID         string
+	CleanTitle      string
+	CleanBody       string
+	AutosavePending bool
+	Confirm         editorConfirm
+	Saving          bool
+	QuitOnSave      bool
+	ModTime         time.Time
+	DiskWarning     string
+	Err             error
 }
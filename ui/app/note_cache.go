@@ -0,0 +1,88 @@
+package app
+
+import (
+	"container/list"
+	"time"
+
+	"github.com/never00rei/a7/journal"
+)
+
+// noteCacheBytes bounds the decrypted note text kept for the dashboard.
+const noteCacheBytes = 8 << 20
+
+type noteCacheKey struct {
+	filename string
+	modTime  time.Time
+}
+
+// loadedNote is what the dashboard shows for a selected note.
+type loadedNote struct {
+	note        *journal.Note
+	attachments int
+}
+
+type noteCacheEntry struct {
+	key    noteCacheKey
+	loaded loadedNote
+	size   int
+}
+
+// noteCache is a least recently used cache of loaded notes, keyed by file
+// and modification time so an edited note is never served stale. It holds
+// decrypted text, so it is cleared whenever the journal is locked or the
+// key changes.
+type noteCache struct {
+	maxBytes int
+	size     int
+	order    *list.List
+	entries  map[noteCacheKey]*list.Element
+}
+
+func newNoteCache(maxBytes int) *noteCache {
+	return &noteCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[noteCacheKey]*list.Element),
+	}
+}
+
+func (c *noteCache) Get(info journal.NoteInfo) (loadedNote, bool) {
+	element, ok := c.entries[noteCacheKey{info.Filename, info.ModTime}]
+	if !ok {
+		return loadedNote{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*noteCacheEntry).loaded, true
+}
+
+func (c *noteCache) Put(info journal.NoteInfo, loaded loadedNote) {
+	key := noteCacheKey{info.Filename, info.ModTime}
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		c.size -= element.Value.(*noteCacheEntry).size
+		delete(c.entries, key)
+	}
+	entry := &noteCacheEntry{key: key, loaded: loaded, size: len(loaded.note.Content) + len(loaded.note.Title)}
+	if entry.size > c.maxBytes {
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	c.size += entry.size
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		evicted := oldest.Value.(*noteCacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, evicted.key)
+		c.size -= evicted.size
+	}
+}
+
+func (c *noteCache) Len() int {
+	return c.order.Len()
+}
+
+func (c *noteCache) Clear() {
+	c.order.Init()
+	c.entries = make(map[noteCacheKey]*list.Element)
+	c.size = 0
+}
@@ -0,0 +1,416 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/never00rei/a7/config"
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/export"
+	"github.com/never00rei/a7/ui/components"
+	"github.com/never00rei/a7/ui/layout"
+	"github.com/never00rei/a7/ui/screens"
+	"github.com/never00rei/a7/ui/theme"
+	"github.com/sahilm/fuzzy"
+)
+
+// paletteCommand is one entry in the command palette. Commands with an
+// ArgPrompt ask for a value before Run is called.
+type paletteCommand struct {
+	Title     string
+	Hint      string
+	ArgPrompt string
+	Arg       func(m AppModel) string
+	Run       func(m *AppModel, arg string) tea.Cmd
+}
+
+type paletteCommands []paletteCommand
+
+func (c paletteCommands) String(i int) string { return c[i].Title }
+func (c paletteCommands) Len() int            { return len(c) }
+
+func (m AppModel) paletteAvailable() bool {
+	switch m.screen {
+	case screenWelcome, screenWalkthroughStorage, screenWalkthroughPrivacy, screenSetup, screenSettings:
+		return false
+	case screenDashboard:
+		return len(m.dashboard.Drafts) == 0
+	}
+	return true
+}
+
+func (m *AppModel) openPalette() tea.Cmd {
+	m.palette.Open = true
+	m.palette.Pending = nil
+	m.palette.Commands = m.paletteCommands()
+	m.palette.Input = textinput.New()
+	m.palette.Input.Prompt = "> "
+	m.palette.Input.Placeholder = "Type a command"
+	m.filterPalette()
+	return m.palette.Input.Focus()
+}
+
+func (m *AppModel) filterPalette() {
+	m.palette.Cursor = 0
+	query := strings.TrimSpace(m.palette.Input.Value())
+	if query == "" {
+		m.palette.Matches = make([]fuzzy.Match, len(m.palette.Commands))
+		for i, command := range m.palette.Commands {
+			m.palette.Matches[i] = fuzzy.Match{Str: command.Title, Index: i}
+		}
+		return
+	}
+	m.palette.Matches = fuzzy.FindFrom(query, paletteCommands(m.palette.Commands))
+}
+
+func (m *AppModel) updatePalette(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		if m.palette.Pending != nil {
+			m.palette.Pending = nil
+			m.palette.Input.SetValue("")
+			m.palette.Input.Placeholder = "Type a command"
+			m.filterPalette()
+			return nil
+		}
+		m.palette.Open = false
+		return nil
+	case "up", "shift+tab":
+		if m.palette.Cursor > 0 {
+			m.palette.Cursor--
+		}
+		return nil
+	case "down", "tab":
+		if m.palette.Cursor < len(m.palette.Matches)-1 {
+			m.palette.Cursor++
+		}
+		return nil
+	case "enter":
+		if pending := m.palette.Pending; pending != nil {
+			arg := strings.TrimSpace(m.palette.Input.Value())
+			if arg == "" {
+				return nil
+			}
+			m.palette.Open = false
+			return pending.Run(m, arg)
+		}
+		if len(m.palette.Matches) == 0 {
+			return nil
+		}
+		command := m.palette.Commands[m.palette.Matches[m.palette.Cursor].Index]
+		if command.ArgPrompt != "" {
+			m.palette.Pending = &command
+			m.palette.Input.Placeholder = ""
+			value := ""
+			if command.Arg != nil {
+				value = command.Arg(*m)
+			}
+			m.palette.Input.SetValue(value)
+			m.palette.Input.CursorEnd()
+			return nil
+		}
+		m.palette.Open = false
+		return command.Run(m, "")
+	}
+
+	var cmd tea.Cmd
+	before := m.palette.Input.Value()
+	m.palette.Input, cmd = m.palette.Input.Update(msg)
+	if m.palette.Pending == nil && m.palette.Input.Value() != before {
+		m.filterPalette()
+	}
+	return cmd
+}
+
+func (m AppModel) paletteItems() []components.PaletteItem {
+	items := make([]components.PaletteItem, 0, len(m.palette.Matches))
+	for _, match := range m.palette.Matches {
+		command := m.palette.Commands[match.Index]
+		items = append(items, components.PaletteItem{Title: command.Title, Hint: command.Hint, Matched: match.MatchedIndexes})
+	}
+	return items
+}
+
+// paletteCommands lists what the palette offers on the current screen. In
+// the editor only editor commands are shown so unsaved text always goes
+// through the editor's own confirmation.
+func (m AppModel) paletteCommands() []paletteCommand {
+	k := m.keys
+	if m.screen == screenEditor {
+		return []paletteCommand{
+			keyCommand("Save entry", k.Save),
+			keyCommand("Toggle Markdown preview", k.Preview),
+			keyCommand("Switch between title and body", k.NextField),
+			keyCommand("Leave editor", k.Back),
+			keyCommand("Quit", k.Quit),
+		}
+	}
+
+	var commands []paletteCommand
+	if m.screen == screenViewer {
+		commands = append(commands,
+			keyCommand("Next entry", k.NextEntry),
+			keyCommand("Previous entry", k.PrevEntry),
+			keyCommand("Find in entry", k.Find),
+			keyCommand("Jump to heading", k.Outline),
+			keyCommand("Edit this entry", k.Edit),
+		)
+	}
+	commands = append(commands, []paletteCommand{
+		dashboardCommand("New entry", k.New),
+		dashboardCommand("New entry from a prompt", k.Prompt),
+		{Title: "Open today", Run: func(m *AppModel, _ string) tea.Cmd { return m.openToday() }},
+		dashboardCommand("Edit selected entry", k.Edit),
+		dashboardCommand("Calendar", k.Calendar),
+		dashboardCommand("Writing stats", k.Stats),
+		dashboardCommand("Tasks", k.Tasks),
+		dashboardCommand("Cycle sort order", k.Sort),
+		dashboardCommand("Reverse sort order", k.Reverse),
+		dashboardCommand("Cycle grouping", k.Group),
+		dashboardCommand("Settings", k.Settings),
+		{
+			Title:     "Switch journal…",
+			ArgPrompt: "Journal folder",
+			Arg:       func(m AppModel) string { return m.config.StoragePath },
+			Run:       func(m *AppModel, arg string) tea.Cmd { return m.switchJournal(arg) },
+		},
+		{
+			Title:     "Export journal…",
+			ArgPrompt: "A folder for HTML, or a .json, .epub or .md file",
+			Run:       func(m *AppModel, arg string) tea.Cmd { return m.exportJournalCmd(arg) },
+		},
+	}...)
+	if m.config.Encrypt {
+		commands = append(commands, paletteCommand{
+			Title:     "Rekey encrypted entries…",
+			ArgPrompt: "New SSH private key",
+			Arg:       func(m AppModel) string { return m.config.SshKeyPath },
+			Run:       func(m *AppModel, arg string) tea.Cmd { return m.rekeyJournalCmd(arg) },
+		})
+	}
+	commands = append(commands,
+		paletteCommand{Title: "Lock journal", Run: func(m *AppModel, _ string) tea.Cmd {
+			m.lockJournal()
+			m.dashboard.Status = "Locked: decrypted entries were cleared from memory"
+			return nil
+		}},
+		paletteCommand{Title: "Toggle theme", Run: func(m *AppModel, _ string) tea.Cmd { return m.toggleTheme() }},
+		paletteCommand{Title: "Show keys", Hint: k.Help.Help().Key, Run: func(m *AppModel, _ string) tea.Cmd {
+			m.showHelp = true
+			return nil
+		}},
+		keyCommand("Quit", k.Quit),
+	)
+	return commands
+}
+
+// keyCommand replays a key binding, so the command goes through exactly the
+// same handling as pressing the key.
+func keyCommand(title string, b key.Binding) paletteCommand {
+	return paletteCommand{Title: title, Hint: b.Help().Key, Run: func(m *AppModel, _ string) tea.Cmd {
+		msg, ok := bindingMsg(b)
+		if !ok {
+			return nil
+		}
+		return func() tea.Msg { return msg }
+	}}
+}
+
+// dashboardCommand replays a dashboard key from any screen.
+func dashboardCommand(title string, b key.Binding) paletteCommand {
+	command := keyCommand(title, b)
+	replay := command.Run
+	command.Run = func(m *AppModel, arg string) tea.Cmd {
+		m.screen = screenDashboard
+		if m.dashboard.List.FilterState() != list.Unfiltered {
+			m.dashboard.List.ResetFilter()
+		}
+		return replay(m, arg)
+	}
+	return command
+}
+
+// bindingMsg rebuilds the key message for a binding's first key.
+func bindingMsg(b key.Binding) (tea.KeyMsg, bool) {
+	keys := b.Keys()
+	if len(keys) == 0 {
+		return tea.KeyMsg{}, false
+	}
+	name := keys[0]
+	var msg tea.KeyMsg
+	if rest, ok := strings.CutPrefix(name, "alt+"); ok && rest != "" {
+		msg.Alt = true
+		name = rest
+	}
+	if runes := []rune(name); len(runes) == 1 {
+		msg.Type = tea.KeyRunes
+		msg.Runes = runes
+		if name == " " {
+			msg.Type = tea.KeySpace
+		}
+		return msg, true
+	}
+	for t := tea.KeyType(-128); t < 128; t++ {
+		if t != tea.KeyRunes && t.String() == name {
+			msg.Type = t
+			return msg, true
+		}
+	}
+	return tea.KeyMsg{}, false
+}
+
+func (m *AppModel) openToday() tea.Cmd {
+	m.openCalendar()
+	if m.calendarDayCount() == 0 {
+		m.startEditorForNew()
+		return nil
+	}
+	updated, cmd := m.openCalendarDay()
+	*m = updated
+	return cmd
+}
+
+func (m *AppModel) switchJournal(path string) tea.Cmd {
+	path = expandHome(path)
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		m.dashboard.Status = fmt.Sprintf("No journal folder at %s", path)
+		m.screen = screenDashboard
+		return nil
+	}
+	m.lockJournal()
+	m.config.StoragePath = path
+	m.dashboard.Status = "Switched to " + path
+	m.screen = screenDashboard
+	*m = m.resetDashboardNotes()
+	return tea.Batch(m.saveConfigCmd(), m.loadDashboardNotesCmd(), m.loadDraftsCmd())
+}
+
+func (m *AppModel) exportJournalCmd(target string) tea.Cmd {
+	target = expandHome(target)
+	m.screen = screenDashboard
+	m.dashboard.Status = "Exporting to " + target + "..."
+	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
+	opts := export.Options{SkipEncrypted: !m.config.Encrypt}
+	return func() tea.Msg {
+		var report export.Report
+		var err error
+		switch strings.ToLower(filepath.Ext(target)) {
+		case ".json":
+			report, err = exportFile(target, func(w io.Writer) (export.Report, error) { return export.JSON(service, w, opts) })
+		case ".epub":
+			report, err = exportFile(target, func(w io.Writer) (export.Report, error) { return export.EPUB(service, w, opts) })
+		case ".md", ".markdown":
+			report, err = exportFile(target, func(w io.Writer) (export.Report, error) { return export.Markdown(service, w, opts) })
+		default:
+			report, err = export.HTML(service, target, opts)
+		}
+		if err != nil {
+			return statusMsg{text: fmt.Sprintf("Export failed: %v", err)}
+		}
+		return statusMsg{text: fmt.Sprintf("Exported %d entries to %s", report.Written, target)}
+	}
+}
+
+func exportFile(target string, write func(io.Writer) (export.Report, error)) (export.Report, error) {
+	file, err := os.Create(target)
+	if err != nil {
+		return export.Report{}, err
+	}
+	report, err := write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return report, err
+}
+
+func (m *AppModel) rekeyJournalCmd(keyPath string) tea.Cmd {
+	keyPath = expandHome(keyPath)
+	m.screen = screenDashboard
+	m.dashboard.Status = "Re-encrypting entries..."
+	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
+	return func() tea.Msg {
+		report, err := service.Rekey(keyPath)
+		return rekeyMsg{keyPath: keyPath, report: report, err: err}
+	}
+}
+
+func (m *AppModel) applyRekey(msg rekeyMsg) tea.Cmd {
+	if msg.err != nil && !msg.report.Rewritten() {
+		m.dashboard.Status = fmt.Sprintf("Rekey failed: %v", msg.err)
+		return nil
+	}
+	// Once anything has been re-encrypted the new key is needed to read it,
+	// so the config follows even when the rekey stopped partway.
+	m.lockJournal()
+	m.config.SshKeyPath = msg.keyPath
+	m.config.SshPubKeyPath = ""
+	if _, err := os.Stat(msg.keyPath + ".pub"); err == nil {
+		m.config.SshPubKeyPath = msg.keyPath + ".pub"
+	}
+	if msg.err != nil {
+		m.dashboard.Status = fmt.Sprintf("Rekey stopped after %d entries and %d attachments: %v; now using the new key, originals are in %s",
+			msg.report.Notes, msg.report.Attachments, msg.err, msg.report.BackupDir)
+	} else {
+		m.dashboard.Status = fmt.Sprintf("Re-encrypted %d entries and %d attachments; originals are in %s",
+			msg.report.Notes, msg.report.Attachments, msg.report.BackupDir)
+	}
+	if len(msg.report.SkippedDrafts) > 0 {
+		m.dashboard.Status += fmt.Sprintf("; drafts left unchanged: %s", strings.Join(msg.report.SkippedDrafts, ", "))
+	}
+	return m.saveConfigCmd()
+}
+
+func (m *AppModel) toggleTheme() tea.Cmd {
+	user, _ := loadThemes()
+	names := theme.Names(user)
+	next := names[0]
+	for i, name := range names {
+		if name == m.config.Theme {
+			next = names[(i+1)%len(names)]
+			break
+		}
+	}
+	m.config.Theme = next
+	m.applyTheme()
+	m.dashboard.Status = "Theme: " + next
+	return m.saveConfigCmd()
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		return filepath.Join(config.Home, rest)
+	}
+	return path
+}
+
+func (m AppModel) paletteView(layout layout.Layout) string {
+	width := layout.PaneContentWidth(screens.PaletteWidth(layout)) - 4
+	m.palette.Input.Width = width - lipgloss.Width(m.palette.Input.Prompt) - 1
+	if pending := m.palette.Pending; pending != nil {
+		return components.FormatPaletteArg(pending.Title, pending.ArgPrompt, m.palette.Input.View())
+	}
+	items := m.paletteItems()
+	cursor := m.palette.Cursor
+	if rows := layout.PaneContentHeight(layout.BodyHeight()) - 2; rows > 0 && len(items) > rows {
+		start := min(max(cursor-rows/2, 0), len(items)-rows)
+		items = items[start : start+rows]
+		cursor -= start
+	}
+	return components.FormatPalette(m.palette.Input.View(), items, cursor, width)
+}
+
+func (m AppModel) paletteHelp() string {
+	if m.palette.Pending != nil {
+		return "enter run • esc back"
+	}
+	return "↑/↓ select • enter run • esc close"
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"time"
+
+	"github.com/never00rei/a7/config"
+	"github.com/never00rei/a7/journal/prompts"
+	"github.com/never00rei/a7/ui/components"
+)
+
+const recentPromptWindow = 30 * 24 * time.Hour
+
+func loadPrompts() ([]prompts.Prompt, error) {
+	dir, err := config.BuildPromptsPath(config.Home, config.XdgConfigHome)
+	if err != nil {
+		return prompts.Builtin(), nil
+	}
+	return prompts.Load(dir)
+}
+
+func (m *AppModel) openPromptPicker() {
+	available, err := loadPrompts()
+	m.promptPicker.Prompts = available
+	m.promptPicker.Err = err
+	m.promptPicker.List.ResetFilter()
+	m.promptPicker.List.SetItems(components.BuildPromptCategoryItems(available))
+	m.promptPicker.List.Select(0)
+	m.screen = screenPromptPicker
+	*m = m.updatePromptPickerSize()
+}
+
+func (m AppModel) updatePromptPickerSize() AppModel {
+	layout := m.layout()
+	width := layout.PaneContentWidth(layout.PrimaryPaneWidth())
+	height := layout.PaneContentHeight(layout.BodyHeight())
+	if height < 0 {
+		height = 0
+	}
+	m.promptPicker.List.SetSize(width, height)
+	return m
+}
+
+func (m AppModel) recentPrompts() []string {
+	cutoff := time.Now().Add(-recentPromptWindow)
+	var recent []string
+	for _, note := range m.dashboard.Notes {
+		if note.Prompt == "" {
+			continue
+		}
+		when := note.Created
+		if when.IsZero() {
+			when = note.ModTime
+		}
+		if when.After(cutoff) {
+			recent = append(recent, note.Prompt)
+		}
+	}
+	return recent
+}
+
+func (m *AppModel) pickPrompt(available []prompts.Prompt, category string) (prompts.Prompt, error) {
+	return prompts.Pick(available, category, m.recentPrompts())
+}
@@ -1,6 +1,10 @@
 package app
 
 import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/never00rei/a7/ui/components"
@@ -32,6 +36,16 @@ func (m *AppModel) activeScreenModel() ScreenModel {
 		return &m.viewer
 	case screenEditor:
 		return &m.editor
+	case screenTemplatePicker:
+		return &m.templatePicker
+	case screenPromptPicker:
+		return &m.promptPicker
+	case screenCalendar:
+		return &m.calendar
+	case screenStats:
+		return &m.stats
+	case screenTasks:
+		return &m.tasks
 	default:
 		return nil
 	}
@@ -58,7 +72,7 @@ func (m *StorageModel) Init(app *AppModel) tea.Cmd {
 
 func (m *StorageModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
 	currentScreen := app.screen
-	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "shift+tab" {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, app.keys.PrevField) {
 		app.screen = prevScreen(app.screen)
 		return app.initActiveFormCmd(), true
 	}
@@ -90,14 +104,14 @@ func (m *PrivacyModel) Init(app *AppModel) tea.Cmd {
 
 func (m *PrivacyModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
 	currentScreen := app.screen
-	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "s" {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "s" {
 		app.config.Encrypt = false
 		app.config.SshKeyPath = ""
 		app.config.SshPubKeyPath = ""
 		app.screen = screenSetup
 		return app.initActiveFormCmd(), true
 	}
-	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "shift+tab" {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, app.keys.PrevField) {
 		app.screen = prevScreen(app.screen)
 		return app.initActiveFormCmd(), true
 	}
@@ -146,7 +160,7 @@ func (m *SettingsModel) Init(app *AppModel) tea.Cmd {
 }
 
 func (m *SettingsModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
-	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "esc" {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, app.keys.Back) {
 		app.screen = screenDashboard
 		return nil, true
 	}
@@ -160,9 +174,19 @@ func (m *SettingsModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
 			app.config.SshKeyPath = ""
 			app.config.SshPubKeyPath = ""
 		}
+		if name := m.Form.GetString(components.ThemeKey); name != "" {
+			app.config.Theme = name
+		}
+		app.applyTheme()
+		app.lockJournal()
 		app.screen = screenDashboard
 		app.resetDashboardNotes()
-		return tea.Batch(app.saveConfigCmd(), app.loadDashboardNotesCmd()), true
+		cmds := []tea.Cmd{app.saveConfigCmd(), app.loadDashboardNotesCmd()}
+		if m.Form.GetBool(components.MigrateLegacyKey) {
+			app.dashboard.Status = "Migrating legacy notes..."
+			cmds = append(cmds, app.migrateLegacyCmd())
+		}
+		return tea.Batch(cmds...), true
 	}
 	if m.Form.State == huh.StateAborted {
 		return tea.Quit, true
@@ -179,15 +203,191 @@ func (m *DashboardModel) Init(app *AppModel) tea.Cmd {
 }
 
 func (m *DashboardModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && len(m.Drafts) > 0 {
+		switch {
+		case key.Matches(keyMsg, app.keys.Open):
+			app.recoverDraft(true)
+		case key.Matches(keyMsg, app.keys.DiscardDraft):
+			app.recoverDraft(false)
+		case key.Matches(keyMsg, app.keys.Back):
+			m.Drafts = nil
+		case key.Matches(keyMsg, app.keys.Quit):
+			return nil, false
+		}
+		return nil, true
+	}
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.Status = ""
+	}
 	var cmd tea.Cmd
+	previous := m.List.Index()
 	m.List, cmd = m.List.Update(msg)
+	components.SkipHeaders(&m.List, m.List.Index() >= previous)
 	app.dashboard.List = m.List
-	app.updateDashboardSelection()
-	return cmd, false
+	return tea.Batch(cmd, app.updateDashboardSelection()), false
 }
 
 func (m *DashboardModel) View(app *AppModel, layout layout.Layout) string {
-	return screens.Dashboard(layout, app.config.StoragePath, m.Err, m.Notes, m.List, m.SelectedNote, m.SelectedErr)
+	status := m.Status
+	if len(m.Drafts) > 0 {
+		status = formatDraftPrompt(m.Drafts)
+	}
+	return screens.Dashboard(layout, app.config.StoragePath, m.Err, m.Notes, m.List, m.SelectedNote, m.SelectedErr, m.SelectedLoading, m.SelectedAttachments, status)
+}
+
+func (m *TemplatePickerModel) Init(app *AppModel) tea.Cmd {
+	return nil
+}
+
+func (m *TemplatePickerModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.List.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(keyMsg, app.keys.Back):
+			app.screen = screenDashboard
+			return nil, true
+		case key.Matches(keyMsg, app.keys.Open):
+			item, ok := m.List.SelectedItem().(components.TemplateItem)
+			if !ok {
+				return nil, true
+			}
+			if item.Template.Name == components.BlankTemplateName {
+				app.startEditorForNew()
+			} else {
+				app.startEditorForTemplate(item.Template)
+			}
+			return nil, true
+		}
+	}
+	var cmd tea.Cmd
+	m.List, cmd = m.List.Update(msg)
+	return cmd, true
+}
+
+func (m *TemplatePickerModel) View(app *AppModel, layout layout.Layout) string {
+	return screens.TemplatePicker(layout, m.List, m.Err)
+}
+
+func (m *PromptPickerModel) Init(app *AppModel) tea.Cmd {
+	return nil
+}
+
+func (m *PromptPickerModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.List.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(keyMsg, app.keys.Back):
+			app.screen = screenDashboard
+			return nil, true
+		case key.Matches(keyMsg, app.keys.Open):
+			item, ok := m.List.SelectedItem().(components.PromptCategoryItem)
+			if !ok {
+				return nil, true
+			}
+			prompt, err := app.pickPrompt(m.Prompts, item.Category)
+			if err != nil {
+				m.Err = err
+				return nil, true
+			}
+			app.startEditorForPrompt(prompt)
+			return nil, true
+		}
+	}
+	var cmd tea.Cmd
+	m.List, cmd = m.List.Update(msg)
+	return cmd, true
+}
+
+func (m *PromptPickerModel) View(app *AppModel, layout layout.Layout) string {
+	return screens.PromptPicker(layout, m.List, m.Err)
+}
+
+func (m *CalendarModel) Init(app *AppModel) tea.Cmd {
+	return nil
+}
+
+func (m *CalendarModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil, false
+	}
+	keys := app.keys
+	if m.ListFocused {
+		switch {
+		case key.Matches(keyMsg, keys.Back, keys.DayList, keys.PrevField):
+			m.ListFocused = false
+			return nil, true
+		case key.Matches(keyMsg, keys.Open):
+			item, ok := m.DayList.SelectedItem().(components.NoteItem)
+			if !ok {
+				return nil, true
+			}
+			updated, cmd := app.openViewerForNote(item.Info, screenCalendar)
+			*app = updated
+			return cmd, true
+		}
+		var cmd tea.Cmd
+		m.DayList, cmd = m.DayList.Update(msg)
+		return cmd, true
+	}
+
+	switch {
+	case key.Matches(keyMsg, keys.Back):
+		app.screen = screenDashboard
+		return nil, true
+	case key.Matches(keyMsg, keys.Left):
+		app.moveCalendarCursor(0, 0, -1)
+	case key.Matches(keyMsg, keys.Right):
+		app.moveCalendarCursor(0, 0, 1)
+	case key.Matches(keyMsg, keys.Up):
+		app.moveCalendarCursor(0, 0, -7)
+	case key.Matches(keyMsg, keys.Down):
+		app.moveCalendarCursor(0, 0, 7)
+	case key.Matches(keyMsg, keys.PrevMonth):
+		app.moveCalendarCursor(0, -1, 0)
+	case key.Matches(keyMsg, keys.NextMonth):
+		app.moveCalendarCursor(0, 1, 0)
+	case key.Matches(keyMsg, keys.PrevYear):
+		app.moveCalendarCursor(-1, 0, 0)
+	case key.Matches(keyMsg, keys.NextYear):
+		app.moveCalendarCursor(1, 0, 0)
+	case key.Matches(keyMsg, keys.Today):
+		app.openCalendar()
+	case key.Matches(keyMsg, keys.DayList):
+		if app.calendarDayCount() > 0 {
+			m.ListFocused = true
+		}
+	case key.Matches(keyMsg, keys.Open):
+		updated, cmd := app.openCalendarDay()
+		*app = updated
+		return cmd, true
+	case key.Matches(keyMsg, keys.Quit, keys.Help):
+		return nil, false
+	}
+	return nil, true
+}
+
+func (m *CalendarModel) View(app *AppModel, layout layout.Layout) string {
+	byDay := components.NotesByDay(app.dashboard.Notes)
+	grid := components.RenderCalendar(m.Cursor, time.Now(), byDay)
+	dayTitle := m.Cursor.Format("Mon 2 Jan 2006")
+	return screens.Calendar(layout, grid, dayTitle, m.DayList, app.calendarDayCount())
+}
+
+func (m *StatsModel) Init(app *AppModel) tea.Cmd {
+	return nil
+}
+
+func (m *StatsModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, app.keys.Back) {
+		app.screen = screenDashboard
+		return nil, true
+	}
+	var cmd tea.Cmd
+	m.Viewport, cmd = m.Viewport.Update(msg)
+	return cmd, false
+}
+
+func (m *StatsModel) View(app *AppModel, layout layout.Layout) string {
+	return screens.Stats(layout, m.Viewport.View())
 }
 
 func (m *ViewerModel) Init(app *AppModel) tea.Cmd {
@@ -195,6 +395,63 @@ func (m *ViewerModel) Init(app *AppModel) tea.Cmd {
 }
 
 func (m *ViewerModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	if isKey && key.Matches(keyMsg, app.keys.Quit) {
+		return nil, false
+	}
+	if m.Finding {
+		if isKey {
+			m.Status = ""
+			return app.updateViewerFind(keyMsg), true
+		}
+		var cmd tea.Cmd
+		m.Find, cmd = m.Find.Update(msg)
+		return cmd, true
+	}
+	if m.OutlineOpen {
+		return app.updateViewerOutline(msg), true
+	}
+	if isKey {
+		m.Status = ""
+		switch s := keyMsg.String(); {
+		case key.Matches(keyMsg, app.keys.Find):
+			return app.startViewerFind(), true
+		case key.Matches(keyMsg, app.keys.NextMatch) && len(m.Matches) > 0:
+			app.jumpViewerMatch(1)
+			return nil, true
+		case key.Matches(keyMsg, app.keys.PrevMatch) && len(m.Matches) > 0:
+			app.jumpViewerMatch(-1)
+			return nil, true
+		case key.Matches(keyMsg, app.keys.Back) && m.Query != "":
+			app.setViewerQuery("")
+			return nil, true
+		case key.Matches(keyMsg, app.keys.NextEntry):
+			updated, cmd := app.stepViewerEntry(1)
+			*app = updated
+			return cmd, true
+		case key.Matches(keyMsg, app.keys.PrevEntry):
+			updated, cmd := app.stepViewerEntry(-1)
+			*app = updated
+			return cmd, true
+		case key.Matches(keyMsg, app.keys.Outline):
+			app.openViewerOutline()
+			return nil, true
+		case key.Matches(keyMsg, app.keys.Attachment) && len(s) == 1 && s >= "1" && s <= "9":
+			return app.openAttachmentCmd(int(s[0] - '1')), true
+		case key.Matches(keyMsg, app.keys.NextLink):
+			app.cycleViewerLink()
+			return nil, true
+		case key.Matches(keyMsg, app.keys.Open) && m.LinkIndex >= 0:
+			updated, cmd := app.followViewerLink()
+			*app = updated
+			return cmd, true
+		case key.Matches(keyMsg, app.keys.Back):
+			if updated, cmd, ok := app.viewerHistoryBack(); ok {
+				*app = updated
+				return cmd, true
+			}
+		}
+	}
 	var cmd tea.Cmd
 	m.Viewport, cmd = m.Viewport.Update(msg)
 	app.viewer.Viewport = m.Viewport
@@ -202,6 +459,9 @@ func (m *ViewerModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
 }
 
 func (m *ViewerModel) View(app *AppModel, layout layout.Layout) string {
+	if m.OutlineOpen {
+		return screens.Viewer(layout, m.Title+" • outline", m.Outline.View())
+	}
 	return screens.Viewer(layout, m.Title, m.Viewport.View())
 }
 
@@ -210,8 +470,41 @@ func (m *EditorModel) Init(app *AppModel) tea.Cmd {
 }
 
 func (m *EditorModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.Paste && m.Body.Focused() {
+		if path := droppedFilePath(string(keyMsg.Runes)); path != "" {
+			app.attachToEditor(path)
+			return nil, true
+		}
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.Saving {
+		if key.Matches(keyMsg, app.keys.Quit) {
+			return tea.Quit, true
+		}
+		return nil, true
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if m.Confirm != confirmNone {
+			return app.confirmEditorExit(keyMsg), true
+		}
+		switch {
+		case key.Matches(keyMsg, app.keys.Preview):
+			return app.toggleEditorPreview(), true
+		case key.Matches(keyMsg, app.keys.Back, app.keys.Quit):
+			if app.editorDirty() {
+				m.Confirm = confirmLeave
+				if key.Matches(keyMsg, app.keys.Quit) {
+					m.Confirm = confirmQuit
+				}
+				return nil, true
+			}
+			app.discardEditorDraft()
+			return nil, false
+		}
+	}
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
+	before := m.Body.Value()
+	beforeTitle := m.Title.Value()
 	m.Title, cmd = m.Title.Update(msg)
 	if cmd != nil {
 		cmds = append(cmds, cmd)
@@ -222,6 +515,17 @@ func (m *EditorModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
 	}
 	app.editor.Title = m.Title
 	app.editor.Body = m.Body
+	if m.Body.Value() != before || m.Title.Value() != beforeTitle {
+		if cmd := app.scheduleAutosave(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	if m.Preview {
+		if m.Body.Value() != before {
+			cmds = append(cmds, app.scheduleEditorPreview())
+		}
+		app.syncEditorPreviewScroll()
+	}
 	if len(cmds) > 0 {
 		return tea.Batch(cmds...), false
 	}
@@ -229,5 +533,44 @@ func (m *EditorModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
 }
 
 func (m *EditorModel) View(app *AppModel, layout layout.Layout) string {
-	return screens.Editor(layout, m.Title.View(), m.Body.View(), m.Err)
+	preview := ""
+	if m.Preview {
+		preview = m.PreviewView.View()
+	}
+	return screens.Editor(layout, m.Title.View(), m.Body.View(), preview, m.Preview, m.Err)
+}
+
+func (m *TasksModel) Init(app *AppModel) tea.Cmd {
+	return nil
+}
+
+func (m *TasksModel) Update(app *AppModel, msg tea.Msg) (tea.Cmd, bool) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.List.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(keyMsg, app.keys.Back):
+			app.screen = screenDashboard
+			return nil, true
+		case key.Matches(keyMsg, app.keys.ToggleTask):
+			return app.toggleSelectedTask(), true
+		case key.Matches(keyMsg, app.keys.ShowDone):
+			m.ShowDone = !m.ShowDone
+			app.refreshTasks()
+			return nil, true
+		case key.Matches(keyMsg, app.keys.Open):
+			item, ok := m.List.SelectedItem().(components.TaskItem)
+			if !ok {
+				return nil, true
+			}
+			updated, cmd := app.openViewerForNote(item.Task.Note, screenTasks)
+			*app = updated
+			return cmd, true
+		}
+	}
+	var cmd tea.Cmd
+	m.List, cmd = m.List.Update(msg)
+	return cmd, true
+}
+
+func (m *TasksModel) View(app *AppModel, layout layout.Layout) string {
+	return screens.Tasks(layout, m.List, m.ShowDone, m.Err)
 }
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"time"
+
+	"github.com/never00rei/a7/journal/stats"
+	"github.com/never00rei/a7/ui/components"
+)
+
+func (m *AppModel) openStats() {
+	m.stats.Summary = stats.Compute(m.dashboard.Notes, time.Now())
+	m.stats.Viewport.YOffset = 0
+	m.screen = screenStats
+	m.updateStatsSize()
+}
+
+func (m *AppModel) updateStatsSize() *AppModel {
+	layout := m.layout()
+	width := layout.PaneContentWidth(layout.ContentWidth())
+	height := layout.PaneContentHeight(layout.BodyHeight())
+	if width <= 0 {
+		width = 1
+	}
+	if height <= 0 {
+		height = 1
+	}
+	m.stats.Viewport.Width = width
+	m.stats.Viewport.Height = height
+	m.stats.Viewport.SetContent(components.FormatStats(m.stats.Summary, width, time.Now()))
+	return m
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/hooks"
+	"github.com/never00rei/a7/ui/components"
+)
+
+func (m *AppModel) openTasks() {
+	m.tasks.List.ResetFilter()
+	m.refreshTasks()
+	m.tasks.List.Select(0)
+	m.screen = screenTasks
+	*m = m.updateTasksSize()
+}
+
+func (m *AppModel) refreshTasks() {
+	if m.config.StoragePath == "" {
+		return
+	}
+	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
+	tasks, err := service.ListTasks()
+	m.tasks.Tasks = tasks
+	m.tasks.Err = err
+	index := m.tasks.List.Index()
+	m.tasks.List.SetItems(components.BuildTaskItems(tasks, m.tasks.ShowDone))
+	if count := len(m.tasks.List.Items()); index >= count && count > 0 {
+		index = count - 1
+	}
+	m.tasks.List.Select(index)
+}
+
+// toggleSelectedTask saves the toggled task in the background, since the
+// save runs the pre-save hooks.
+func (m *AppModel) toggleSelectedTask() tea.Cmd {
+	item, ok := m.tasks.List.SelectedItem().(components.TaskItem)
+	if !ok {
+		return nil
+	}
+	path, encrypt, keyPath := m.config.StoragePath, m.config.Encrypt, m.config.SshKeyPath
+	return func() tea.Msg {
+		var deferred []hooks.Call
+		service := journal.NewService(path, journal.WithEncryption(encrypt, keyPath), journal.WithHooks(hookRunner(&deferred)))
+		err := service.ToggleTask(item.Task)
+		return taskToggledMsg{hooks: deferred, err: err}
+	}
+}
+
+func (m *AppModel) applyTaskToggled(msg taskToggledMsg) tea.Cmd {
+	m.refreshTasks()
+	if msg.err != nil {
+		m.tasks.Err = msg.err
+	}
+	return runHooksCmd(msg.hooks)
+}
+
+func (m AppModel) updateTasksSize() AppModel {
+	layout := m.layout()
+	width := layout.PaneContentWidth(layout.PrimaryPaneWidth())
+	height := layout.PaneContentHeight(layout.BodyHeight())
+	if height < 0 {
+		height = 0
+	}
+	m.tasks.List.SetSize(width, height)
+	return m
+}
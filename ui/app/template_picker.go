@@ -0,0 +1,48 @@
+package app
+
+import (
+	"path/filepath"
+
+	"github.com/never00rei/a7/config"
+	"github.com/never00rei/a7/journal/templates"
+	"github.com/never00rei/a7/ui/components"
+)
+
+func loadTemplates() ([]templates.Template, error) {
+	dir, err := config.BuildTemplatesPath(config.Home, config.XdgConfigHome)
+	if err != nil {
+		return templates.Load("")
+	}
+	if err := templates.Seed(dir); err != nil {
+		return nil, err
+	}
+	return templates.Load(dir)
+}
+
+func (m *AppModel) openTemplatePicker() {
+	available, err := loadTemplates()
+	m.templatePicker.Err = err
+	m.templatePicker.List.ResetFilter()
+	m.templatePicker.List.SetItems(components.BuildTemplateItems(available))
+	m.templatePicker.List.Select(0)
+	m.screen = screenTemplatePicker
+	*m = m.updateTemplatePickerSize()
+}
+
+func (m AppModel) updateTemplatePickerSize() AppModel {
+	layout := m.layout()
+	width := layout.PaneContentWidth(layout.PrimaryPaneWidth())
+	height := layout.PaneContentHeight(layout.BodyHeight())
+	if height < 0 {
+		height = 0
+	}
+	m.templatePicker.List.SetSize(width, height)
+	return m
+}
+
+func (m AppModel) journalName() string {
+	if m.config.StoragePath == "" {
+		return ""
+	}
+	return filepath.Base(filepath.Clean(m.config.StoragePath))
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/never00rei/a7/config"
+	"github.com/never00rei/a7/ui/theme"
+)
+
+func loadThemes() ([]theme.Theme, error) {
+	dir, err := config.BuildThemesPath(config.Home, config.XdgConfigHome)
+	if err != nil {
+		return nil, nil
+	}
+	return theme.Load(dir)
+}
+
+// applyTheme makes the configured theme current and re-renders the open
+// note so its Markdown picks up the new style.
+func (m *AppModel) applyTheme() {
+	user, err := loadThemes()
+	if err != nil {
+		m.dashboard.Status = fmt.Sprintf("Themes: %v", err)
+	}
+	theme.SetCurrent(theme.Resolve(m.config.Theme, user, lipgloss.HasDarkBackground))
+	m.renderViewerContent()
+}
+
+func themeNames() []string {
+	user, _ := loadThemes()
+	return theme.Names(user)
+}
+
+func markdownStyle() glamour.TermRendererOption {
+	style := theme.CurrentTheme().Markdown
+	if _, ok := styles.DefaultStyles[style]; ok || style == styles.AutoStyle {
+		return glamour.WithStandardStyle(style)
+	}
+	return glamour.WithStylesFromJSONFile(style)
+}
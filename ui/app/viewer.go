@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/hooks"
 	"github.com/never00rei/a7/ui/components"
 )
 
@@ -20,14 +21,32 @@ func (m AppModel) openViewer() (AppModel, tea.Cmd) {
 	if !ok {
 		return m, nil
 	}
+	return m.openViewerForNote(noteItem.Info, screenDashboard)
+}
 
-	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
-	note, err := service.LoadNote(noteItem.Info.Filename)
+func (m AppModel) openViewerForNote(info journal.NoteInfo, back screenID) (AppModel, tea.Cmd) {
+	if m.config.StoragePath == "" {
+		return m, nil
+	}
+
+	m.viewer.Back = back
+	m.viewer.History = nil
+	m.viewer.Links = nil
+	m.viewer.Backlinks = nil
+	m.viewer.LinkIndex = -1
+	m.viewer.Finding = false
+	m.viewer.Query = ""
+	m.viewer.Matches = nil
+	m.viewer.OutlineOpen = false
+	var deferred []hooks.Call
+	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath), journal.WithHooks(hookRunner(&deferred)))
+	note, err := service.OpenNote(info.Filename)
 	if err != nil {
 		m.viewer.Title = "Unable to load journal"
 		m.viewer.Viewport.SetContent(fmt.Sprintf("Error: %v", err))
 		m.viewer.Viewport.YOffset = 0
 		m.viewer.Note = nil
+		m.viewer.Attachments = nil
 		m.screen = screenViewer
 		m.updateViewerSize()
 		return m, nil
@@ -35,16 +54,86 @@ func (m AppModel) openViewer() (AppModel, tea.Cmd) {
 
 	title := note.Title
 	if title == "" {
-		title = noteItem.Info.Filename
+		title = info.Filename
 	}
 
 	m.viewer.Title = title
 	m.viewer.Raw = note.Content
+	m.viewer.Attachments, _ = service.ListAttachments(info.Filename)
+	if notes, err := service.ListNotes(); err == nil {
+		m.viewer.Links = journal.Links(notes, note.Content)
+	}
+	m.viewer.Status = ""
 	m.viewer.Viewport.YOffset = 0
 	m.viewer.Note = note
 	m.screen = screenViewer
 	m.updateViewerSize()
-	return m, nil
+	return m, tea.Batch(m.loadBacklinksCmd(info.Filename), runHooksCmd(deferred))
+}
+
+// loadBacklinksCmd finds the notes linking to filename in the background,
+// since that means reading and decrypting every note in the journal.
+func (m AppModel) loadBacklinksCmd(filename string) tea.Cmd {
+	service := journal.NewService(m.config.StoragePath, journal.WithEncryption(m.config.Encrypt, m.config.SshKeyPath))
+	return func() tea.Msg {
+		backlinks, _ := service.Backlinks(filename)
+		return backlinksMsg{filename: filename, backlinks: backlinks}
+	}
+}
+
+func (m *AppModel) applyBacklinks(msg backlinksMsg) {
+	if m.viewer.Note == nil || m.viewer.Note.Filename != msg.filename {
+		return
+	}
+	m.viewer.Backlinks = msg.backlinks
+	m.renderViewerContent()
+}
+
+func (m AppModel) viewerLinkTargets() []journal.NoteInfo {
+	targets := make([]journal.NoteInfo, 0, len(m.viewer.Links)+len(m.viewer.Backlinks))
+	targets = append(targets, m.viewer.Links...)
+	return append(targets, m.viewer.Backlinks...)
+}
+
+func (m *AppModel) cycleViewerLink() {
+	count := len(m.viewerLinkTargets())
+	if count == 0 {
+		return
+	}
+	m.viewer.LinkIndex = (m.viewer.LinkIndex + 1) % count
+	m.renderViewerContent()
+}
+
+// followViewerLink opens the selected link or backlink, remembering the
+// current note so esc can walk back through followed links.
+func (m AppModel) followViewerLink() (AppModel, tea.Cmd) {
+	targets := m.viewerLinkTargets()
+	if m.viewer.LinkIndex < 0 || m.viewer.LinkIndex >= len(targets) || m.viewer.Note == nil {
+		return m, nil
+	}
+	current := journal.NoteInfo{Filename: m.viewer.Note.Filename, Title: m.viewer.Note.Title}
+	history := append(m.viewer.History, current)
+	m, cmd := m.openViewerForNote(targets[m.viewer.LinkIndex], m.viewer.Back)
+	m.viewer.History = history
+	return m, cmd
+}
+
+func (m AppModel) viewerHistoryBack() (AppModel, tea.Cmd, bool) {
+	if len(m.viewer.History) == 0 {
+		return m, nil, false
+	}
+	history := m.viewer.History
+	previous := history[len(history)-1]
+	m, cmd := m.openViewerForNote(previous, m.viewer.Back)
+	m.viewer.History = history[:len(history)-1]
+	return m, cmd, true
+}
+
+func (m AppModel) viewerBackScreen() screenID {
+	if m.viewer.Back == screenCalendar || m.viewer.Back == screenTasks {
+		return m.viewer.Back
+	}
+	return screenDashboard
 }
 
 func (m *AppModel) updateViewerSize() *AppModel {
@@ -65,21 +154,25 @@ func (m *AppModel) updateViewerSize() *AppModel {
 	}
 	m.viewer.Viewport.Width = width
 	m.viewer.Viewport.Height = height
+	m.viewer.Outline.SetSize(width, height)
 	m.renderViewerContent()
 	return m
 }
 
 func (m *AppModel) renderViewerContent() {
+	attachments := components.FormatAttachments(m.viewer.Attachments) +
+		components.FormatLinks(m.viewer.Links, m.viewer.Backlinks, m.viewer.LinkIndex)
+	defer m.refreshViewerFind()
 	if m.viewer.Raw == "" {
-		m.viewer.Viewport.SetContent("This journal is empty.")
+		m.viewer.Rendered = "This journal is empty." + attachments
 		return
 	}
 	rendered, err := renderMarkdown(m.viewer.Viewport.Width, m.viewer.Raw)
 	if err != nil || strings.TrimSpace(rendered) == "" {
-		m.viewer.Viewport.SetContent(m.viewer.Raw)
+		m.viewer.Rendered = m.viewer.Raw + attachments
 		return
 	}
-	m.viewer.Viewport.SetContent(rendered)
+	m.viewer.Rendered = rendered + attachments
 }
 
 func renderMarkdown(width int, content string) (string, error) {
@@ -87,7 +180,7 @@ func renderMarkdown(width int, content string) (string, error) {
 		width = 80
 	}
 	renderer, err := glamour.NewTermRenderer(
-		glamour.WithStandardStyle("dark"),
+		markdownStyle(),
 		glamour.WithWordWrap(width),
 	)
 	if err != nil {
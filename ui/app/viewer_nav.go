@@ -0,0 +1,164 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/never00rei/a7/journal/codec"
+	"github.com/never00rei/a7/ui/components"
+)
+
+// stepViewerEntry opens the entry before or after the current one in
+// dashboard order and moves the dashboard cursor along with it.
+func (m AppModel) stepViewerEntry(delta int) (AppModel, tea.Cmd) {
+	if m.viewer.Note == nil {
+		return m, nil
+	}
+	items := m.dashboard.List.VisibleItems()
+	current := -1
+	for i, item := range items {
+		if note, ok := item.(components.NoteItem); ok && note.Info.Filename == m.viewer.Note.Filename {
+			current = i
+			break
+		}
+	}
+	if current < 0 {
+		m.viewer.Status = "Entry is not in the dashboard list"
+		return m, nil
+	}
+	for i := current + delta; i >= 0 && i < len(items); i += delta {
+		note, ok := items[i].(components.NoteItem)
+		if !ok {
+			continue
+		}
+		m.dashboard.List.Select(i)
+		selectionCmd := m.updateDashboardSelection()
+		m, cmd := m.openViewerForNote(note.Info, m.viewer.Back)
+		return m, tea.Batch(selectionCmd, cmd)
+	}
+	if delta > 0 {
+		m.viewer.Status = "No later entry"
+	} else {
+		m.viewer.Status = "No earlier entry"
+	}
+	return m, nil
+}
+
+func (m *AppModel) startViewerFind() tea.Cmd {
+	m.viewer.Finding = true
+	m.viewer.Find.SetValue(m.viewer.Query)
+	m.viewer.Find.CursorEnd()
+	return m.viewer.Find.Focus()
+}
+
+// updateViewerFind searches as the query is typed; enter keeps the matches
+// for n/N and esc drops them.
+func (m *AppModel) updateViewerFind(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.viewer.Finding = false
+		m.viewer.Find.Blur()
+		m.setViewerQuery("")
+		return nil
+	case "enter":
+		m.viewer.Finding = false
+		m.viewer.Find.Blur()
+		if len(m.viewer.Matches) == 0 && m.viewer.Query != "" {
+			m.viewer.Status = fmt.Sprintf("No matches for %q", m.viewer.Query)
+		}
+		return nil
+	}
+	var cmd tea.Cmd
+	m.viewer.Find, cmd = m.viewer.Find.Update(msg)
+	if m.viewer.Find.Value() != m.viewer.Query {
+		m.setViewerQuery(m.viewer.Find.Value())
+	}
+	return cmd
+}
+
+func (m *AppModel) setViewerQuery(query string) {
+	m.viewer.Query = query
+	m.viewer.MatchIndex = 0
+	m.refreshViewerFind()
+	// Start from the first match at or below the top of the page.
+	for i, line := range m.viewer.Matches {
+		if line >= m.viewer.Viewport.YOffset {
+			m.viewer.MatchIndex = i
+			break
+		}
+	}
+	m.jumpViewerMatch(0)
+}
+
+// refreshViewerFind re-applies the search to the rendered note, which also
+// runs whenever the note is re-rendered for a new width or theme.
+func (m *AppModel) refreshViewerFind() {
+	m.viewer.Matches = components.FindLines(m.viewer.Rendered, m.viewer.Query)
+	if m.viewer.MatchIndex >= len(m.viewer.Matches) {
+		m.viewer.MatchIndex = 0
+	}
+	m.viewer.Viewport.SetContent(components.HighlightFind(m.viewer.Rendered, m.viewer.Query, m.viewer.Matches, m.viewer.MatchIndex))
+}
+
+func (m *AppModel) jumpViewerMatch(delta int) {
+	count := len(m.viewer.Matches)
+	if count == 0 {
+		return
+	}
+	m.viewer.MatchIndex = ((m.viewer.MatchIndex+delta)%count + count) % count
+	m.refreshViewerFind()
+	m.scrollViewerTo(m.viewer.Matches[m.viewer.MatchIndex])
+}
+
+// scrollViewerTo brings a content line into view a third of the way down
+// the page.
+func (m *AppModel) scrollViewerTo(line int) {
+	offset := line - m.viewer.Viewport.Height/3
+	if offset < 0 {
+		offset = 0
+	}
+	m.viewer.Viewport.SetYOffset(offset)
+}
+
+func (m *AppModel) openViewerOutline() {
+	headings := codec.ParseHeadings(m.viewer.Raw)
+	if len(headings) == 0 {
+		m.viewer.Status = "No headings in this entry"
+		return
+	}
+	m.viewer.Outline.ResetFilter()
+	m.viewer.Outline.SetItems(components.BuildOutlineItems(headings, m.viewer.Rendered))
+	m.viewer.Outline.Select(0)
+	m.viewer.OutlineOpen = true
+}
+
+func (m *AppModel) updateViewerOutline(msg tea.Msg) tea.Cmd {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.viewer.Outline.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(keyMsg, m.keys.Back, m.keys.Outline):
+			m.viewer.OutlineOpen = false
+			return nil
+		case key.Matches(keyMsg, m.keys.Open):
+			if item, ok := m.viewer.Outline.SelectedItem().(components.OutlineItem); ok {
+				m.scrollViewerTo(item.Line)
+			}
+			m.viewer.OutlineOpen = false
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	m.viewer.Outline, cmd = m.viewer.Outline.Update(msg)
+	return cmd
+}
+
+func (m AppModel) viewerFindStatus() string {
+	if m.viewer.Query == "" {
+		return ""
+	}
+	if len(m.viewer.Matches) == 0 {
+		return fmt.Sprintf("no matches for %q", m.viewer.Query)
+	}
+	return fmt.Sprintf("match %d/%d for %q", m.viewer.MatchIndex+1, len(m.viewer.Matches), m.viewer.Query)
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/never00rei/a7/journal"
+)
+
+// watchJournals turns the journal folder watcher on; tests switch it off so
+// commands never block waiting for file events.
+var watchJournals = true
+
+const journalWatchDebounce = 200 * time.Millisecond
+
+func (m AppModel) startWatchCmd() tea.Cmd {
+	if !watchJournals || m.config.StoragePath == "" {
+		return nil
+	}
+	if m.watcher != nil && m.watcher.Root == m.config.StoragePath {
+		return nil
+	}
+	path := m.config.StoragePath
+	return func() tea.Msg {
+		watcher, err := journal.NewService(path).Watch(journalWatchDebounce)
+		return watchStartedMsg{path: path, watcher: watcher, err: err}
+	}
+}
+
+func (m *AppModel) applyWatchStarted(msg watchStartedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.dashboard.Status = fmt.Sprintf("Not watching for changes: %v", msg.err)
+		return nil
+	}
+	if msg.path != m.config.StoragePath || (m.watcher != nil && m.watcher.Root == msg.path) {
+		msg.watcher.Close()
+		return nil
+	}
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+	m.watcher = msg.watcher
+	return waitForJournalChanges(m.watcher)
+}
+
+// stopWatching closes the journal watcher, when the app exits or moves to
+// another journal.
+func (m *AppModel) stopWatching() {
+	if m.watcher != nil {
+		m.watcher.Close()
+		m.watcher = nil
+	}
+}
+
+func waitForJournalChanges(watcher *journal.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case changes, ok := <-watcher.Changes:
+			if !ok {
+				return nil
+			}
+			return journalChangedMsg{watcher: watcher, changes: changes}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return journalChangedMsg{watcher: watcher, err: err}
+		}
+	}
+}
+
+// applyJournalChanges folds changed notes into the dashboard without
+// reloading the whole journal, and warns the editor when the note it has
+// open was changed by something else.
+func (m *AppModel) applyJournalChanges(msg journalChangedMsg) tea.Cmd {
+	if msg.watcher != m.watcher {
+		return nil
+	}
+	next := waitForJournalChanges(m.watcher)
+	if msg.err != nil {
+		m.dashboard.Status = fmt.Sprintf("Watching journal: %v", msg.err)
+		return next
+	}
+
+	service := journal.NewService(m.config.StoragePath)
+	for _, change := range msg.changes {
+		index := -1
+		for i, info := range m.dashboard.Notes {
+			if info.Filename == change.Filename {
+				index = i
+				break
+			}
+		}
+		var info journal.NoteInfo
+		var err error
+		if !change.Removed {
+			info, err = service.StatNote(change.Filename)
+		}
+		switch {
+		case change.Removed || err != nil:
+			if index >= 0 {
+				m.dashboard.Notes = append(m.dashboard.Notes[:index], m.dashboard.Notes[index+1:]...)
+			}
+		case index >= 0:
+			m.dashboard.Notes[index] = info
+		default:
+			m.dashboard.Notes = append(m.dashboard.Notes, info)
+		}
+		if m.screen == screenEditor && change.Filename == m.editor.File {
+			m.warnEditorChanged(change.Removed || err != nil, info)
+		}
+	}
+
+	m.refreshDashboardItems()
+	*m = m.updateDashboardListSize()
+	if m.screen == screenCalendar {
+		m.refreshCalendarDay()
+	}
+	return tea.Batch(m.updateDashboardSelection(), next)
+}
+
+func (m *AppModel) warnEditorChanged(removed bool, info journal.NoteInfo) {
+	switch {
+	case removed:
+		m.editor.DiskWarning = "This entry was deleted on disk; saving will recreate it"
+	case !info.ModTime.Equal(m.editor.ModTime):
+		m.editor.DiskWarning = "This entry changed on disk; saving will overwrite those changes"
+	}
+}
@@ -0,0 +1,44 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/never00rei/a7/journal"
+)
+
+// FormatAttachments lists a note's attachments under its rendered body,
+// numbered for the viewer's open keys.
+func FormatAttachments(attachments []journal.Attachment) string {
+	if len(attachments) == 0 {
+		return ""
+	}
+	lines := []string{"", metadataLabelStyle.Render("Attachments")}
+	for i, attachment := range attachments {
+		key := "  "
+		if i < 9 {
+			key = fmt.Sprintf("%d.", i+1)
+		}
+		line := fmt.Sprintf("  %s %s (%s", key, attachment.Name, FormatSize(attachment.Size))
+		if attachment.Encrypted {
+			line += ", encrypted"
+		}
+		lines = append(lines, line+")")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size)
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		value /= unit
+		if value < unit || suffix == "GB" {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", size)
+}
@@ -0,0 +1,99 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/ui/theme"
+)
+
+const dayKeyLayout = "2006-01-02"
+
+var (
+	calendarHeaderStyle = lipgloss.NewStyle().Bold(true)
+	calendarEntryStyle  = lipgloss.NewStyle().Bold(true)
+	calendarTodayStyle  = lipgloss.NewStyle().Underline(true)
+	calendarCursorStyle = lipgloss.NewStyle().Reverse(true)
+	calendarCountDigits = []string{"", "¹", "²", "³", "⁴", "⁵", "⁶", "⁷", "⁸", "⁹"}
+)
+
+func DayKey(t time.Time) string {
+	return t.Format(dayKeyLayout)
+}
+
+func NotesByDay(notes []journal.NoteInfo) map[string][]journal.NoteInfo {
+	byDay := make(map[string][]journal.NoteInfo)
+	for _, note := range notes {
+		date, ok := note.Date()
+		if !ok {
+			continue
+		}
+		key := DayKey(date.Local())
+		byDay[key] = append(byDay[key], note)
+	}
+	return byDay
+}
+
+func RenderCalendar(cursor, today time.Time, byDay map[string][]journal.NoteInfo) string {
+	first := time.Date(cursor.Year(), cursor.Month(), 1, 0, 0, 0, 0, cursor.Location())
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+	// Weeks start on Monday.
+	offset := (int(first.Weekday()) + 6) % 7
+
+	lines := []string{
+		calendarHeaderStyle.Render(first.Format("January 2006")),
+		"",
+		"Mo   Tu   We   Th   Fr   Sa   Su",
+	}
+
+	cells := make([]string, 0, 42)
+	for i := 0; i < offset; i++ {
+		cells = append(cells, "    ")
+	}
+	total := 0
+	for day := 1; day <= daysInMonth; day++ {
+		date := time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, first.Location())
+		count := len(byDay[DayKey(date)])
+		total += count
+		cells = append(cells, renderDayCell(date, count, cursor, today))
+	}
+
+	for start := 0; start < len(cells); start += 7 {
+		end := start + 7
+		if end > len(cells) {
+			end = len(cells)
+		}
+		lines = append(lines, strings.Join(cells[start:end], " "))
+	}
+
+	lines = append(lines, "", fmt.Sprintf("%d entries this month", total))
+	return strings.Join(lines, "\n")
+}
+
+func renderDayCell(date time.Time, count int, cursor, today time.Time) string {
+	label := fmt.Sprintf("%2d", date.Day())
+	style := lipgloss.NewStyle()
+	if count > 0 {
+		style = calendarEntryStyle.Foreground(theme.CurrentTheme().Accent)
+	}
+	if DayKey(date) == DayKey(today) {
+		style = style.Inherit(calendarTodayStyle)
+	}
+	if DayKey(date) == DayKey(cursor) {
+		style = style.Inherit(calendarCursorStyle)
+	}
+	return style.Render(label) + fmt.Sprintf("%-2s", countLabel(count))
+}
+
+func countLabel(count int) string {
+	if count <= 0 {
+		return ""
+	}
+	if count < len(calendarCountDigits) {
+		return calendarCountDigits[count]
+	}
+	return "⁺"
+}
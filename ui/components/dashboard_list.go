@@ -8,12 +8,18 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/journal/codec"
+	"github.com/never00rei/a7/ui/theme"
 )
 
 const DashboardLeftRatio = 0.6
 
 var metadataLabelStyle = lipgloss.NewStyle().Bold(true)
 
+func StatusStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(theme.CurrentTheme().Accent)
+}
+
 type NoteItem struct {
 	Info journal.NoteInfo
 }
@@ -39,7 +45,7 @@ func (n NoteItem) FilterValue() string {
 }
 
 func NewNotesList(items []list.Item, width, height int) list.Model {
-	l := list.New(items, list.NewDefaultDelegate(), width, height)
+	l := list.New(items, notesDelegate{list.NewDefaultDelegate()}, width, height)
 	l.SetShowHelp(false)
 	l.SetShowPagination(true)
 	l.SetShowStatusBar(true)
@@ -58,18 +64,12 @@ func BuildNoteItems(notes []journal.NoteInfo) []list.Item {
 }
 
 func ParseFilenameTimestamp(filename string) (time.Time, bool) {
-	if len(filename) < len("2006-01-02_15-04") {
-		return time.Time{}, false
-	}
-	ts := filename[:len("2006-01-02_15-04")]
-	parsed, err := time.Parse("2006-01-02_15-04", ts)
-	if err != nil {
-		return time.Time{}, false
-	}
-	return parsed, true
+	return codec.ParseFilenameTimestamp(filename)
 }
 
-func FormatSelectedMeta(item list.Item, total int, note *journal.Note, loadErr error) string {
+// FormatSelectedMeta describes the selected note. While the note itself is
+// still loading only the details from the listing are shown.
+func FormatSelectedMeta(item list.Item, total int, note *journal.Note, loadErr error, loading bool, attachments int) string {
 	if item == nil {
 		return fmt.Sprintf("%s: %d\n\nSelect a journal to see details.", boldLabel("Total journals"), total)
 	}
@@ -82,10 +82,15 @@ func FormatSelectedMeta(item list.Item, total int, note *journal.Note, loadErr e
 	lines := []string{
 		boldLabel("Title"),
 		noteTitle(noteItem),
+	}
+	if loading {
+		lines = append(lines, StatusStyle().Render("Loading entry…"))
+	}
+	lines = append(lines,
 		"",
 		boldLabel("Last modified"),
 		noteItem.Info.ModTime.Local().Format(time.RFC822),
-	}
+	)
 
 	created := time.Time{}
 	if note != nil && !note.Created.IsZero() {
@@ -116,13 +121,26 @@ func FormatSelectedMeta(item list.Item, total int, note *journal.Note, loadErr e
 		wordCount = note.WordCount
 	}
 	if wordCount >= 0 {
-		lines = append(lines, "", boldLabel("Word count"), fmt.Sprintf("%d", wordCount))
+		lines = append(lines, "", boldLabel("Word count"), fmt.Sprintf("%d (%s)", wordCount, ReadingTime(wordCount)))
+	} else if loading {
+		lines = append(lines, "", boldLabel("Word count"), "…")
 	} else if loadErr != nil {
 		lines = append(lines, "", boldLabel("Word count"), "Unavailable")
 	} else {
 		lines = append(lines, "", boldLabel("Word count"), "Unavailable")
 	}
 
+	tags := noteItem.Info.Tags
+	if note != nil && len(note.Tags) > 0 {
+		tags = note.Tags
+	}
+	if len(tags) > 0 {
+		lines = append(lines, "", boldLabel("Tags"), "#"+strings.Join(tags, " #"))
+	}
+	if attachments > 0 {
+		lines = append(lines, "", boldLabel("Attachments"), fmt.Sprintf("%d", attachments))
+	}
+
 	lines = append(lines, "", fmt.Sprintf("%s: %d", boldLabel("Total journals"), total))
 
 	return strings.Join(lines, "\n")
@@ -0,0 +1,65 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/never00rei/a7/ui/theme"
+)
+
+// FindLines returns the lines of rendered content whose visible text
+// contains query, ignoring case.
+func FindLines(content, query string) []int {
+	query = strings.ToLower(query)
+	if query == "" {
+		return nil
+	}
+	var lines []int
+	for i, line := range strings.Split(content, "\n") {
+		if strings.Contains(strings.ToLower(ansi.Strip(line)), query) {
+			lines = append(lines, i)
+		}
+	}
+	return lines
+}
+
+// HighlightFind marks every occurrence of query in the matching lines. The
+// styling of those lines is dropped so the marks stay readable; the line
+// holding the current match gets the accent colour.
+func HighlightFind(content, query string, matches []int, current int) string {
+	if query == "" || len(matches) == 0 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	matchStyle := lipgloss.NewStyle().Reverse(true)
+	currentStyle := lipgloss.NewStyle().Background(theme.CurrentTheme().Accent).Foreground(lipgloss.Color("0"))
+	for i, line := range matches {
+		if line < 0 || line >= len(lines) {
+			continue
+		}
+		style := matchStyle
+		if i == current {
+			style = currentStyle
+		}
+		lines[line] = markOccurrences(ansi.Strip(lines[line]), query, style)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func markOccurrences(text, query string, style lipgloss.Style) string {
+	lower := strings.ToLower(text)
+	query = strings.ToLower(query)
+	var b strings.Builder
+	for {
+		index := strings.Index(lower, query)
+		if index < 0 || len(lower) != len(text) {
+			b.WriteString(text)
+			return b.String()
+		}
+		b.WriteString(text[:index])
+		b.WriteString(style.Render(text[index : index+len(query)]))
+		text = text[index+len(query):]
+		lower = lower[index+len(query):]
+	}
+}
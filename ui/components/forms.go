@@ -12,6 +12,8 @@ const (
 	SshKeyPathKey    = "ssh_key_path"
 	SshPubKeyPathKey = "ssh_pub_key_path"
 	EncryptKey       = "encrypt"
+	MigrateLegacyKey = "migrate_legacy"
+	ThemeKey         = "theme"
 )
 
 func NewStorageForm(path *string, width int) *huh.Form {
@@ -80,7 +82,7 @@ func NewPrivacyForm(encrypt *bool, sshKeyPath *string, sshPubKeyPath *string, wi
 	return form
 }
 
-func NewSettingsForm(path *string, encrypt *bool, sshKeyPath *string, sshPubKeyPath *string, width int) *huh.Form {
+func NewSettingsForm(path *string, encrypt *bool, sshKeyPath *string, sshPubKeyPath *string, themeName *string, themes []string, width int) *huh.Form {
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
@@ -126,6 +128,22 @@ func NewSettingsForm(path *string, encrypt *bool, sshKeyPath *string, sshPubKeyP
 			}
 			return !*encrypt
 		}),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key(ThemeKey).
+				Value(themeName).
+				Title("Theme").
+				Description("auto follows your terminal background. Add your own themes to the themes folder in the config directory.").
+				Options(huh.NewOptions(themes...)...),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key(MigrateLegacyKey).
+				Title("Migrate legacy notes?").
+				Description("Rewrite notes that still use the old \"# timestamp Title\" header into front matter.\nOriginals are backed up to .backup in the journal folder.").
+				Affirmative("Migrate").
+				Negative("Skip"),
+		),
 	).WithShowHelp(false)
 
 	if width > 0 {
@@ -0,0 +1,39 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/never00rei/a7/ui/keys"
+)
+
+const keyHelpColumns = 3
+
+// FormatKeyHelp lays out every binding under its screen heading for the help
+// overlay, three groups per row.
+func FormatKeyHelp(groups []keys.Group) string {
+	var rows []string
+	for start := 0; start < len(groups); start += keyHelpColumns {
+		end := min(start+keyHelpColumns, len(groups))
+		var blocks []string
+		for _, group := range groups[start:end] {
+			blocks = append(blocks, lipgloss.NewStyle().PaddingRight(4).Render(formatKeyGroup(group)))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, blocks...))
+	}
+	return strings.Join(rows, "\n\n")
+}
+
+func formatKeyGroup(group keys.Group) string {
+	var b strings.Builder
+	b.WriteString(boldLabel(group.Title))
+	for _, binding := range group.Bindings {
+		if !binding.Enabled() {
+			continue
+		}
+		help := binding.Help()
+		fmt.Fprintf(&b, "\n%-12s %s", help.Key, help.Desc)
+	}
+	return b.String()
+}
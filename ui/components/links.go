@@ -0,0 +1,41 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/never00rei/a7/journal"
+)
+
+var selectedLinkStyle = lipgloss.NewStyle().Reverse(true)
+
+// FormatLinks lists a note's outgoing links and backlinks under its body.
+// selected indexes the combined list, links first.
+func FormatLinks(links, backlinks []journal.NoteInfo, selected int) string {
+	if len(links) == 0 && len(backlinks) == 0 {
+		return ""
+	}
+	var lines []string
+	index := 0
+	section := func(title string, notes []journal.NoteInfo) {
+		if len(notes) == 0 {
+			return
+		}
+		lines = append(lines, "", metadataLabelStyle.Render(title))
+		for _, note := range notes {
+			label := note.Title
+			if label == "" {
+				label = journal.NoteID(note.Filename)
+			}
+			if index == selected {
+				lines = append(lines, "  › "+selectedLinkStyle.Render(label))
+			} else {
+				lines = append(lines, "    "+label)
+			}
+			index++
+		}
+	}
+	section("Links", links)
+	section("Backlinks", backlinks)
+	return strings.Join(lines, "\n") + "\n"
+}
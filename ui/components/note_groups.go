@@ -0,0 +1,169 @@
+package components
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/ui/theme"
+)
+
+type GroupMode string
+
+const (
+	GroupNone  GroupMode = "none"
+	GroupYear  GroupMode = "year"
+	GroupMonth GroupMode = "month"
+)
+
+var GroupModes = []GroupMode{GroupNone, GroupYear, GroupMonth}
+
+var groupHeaderStyle = lipgloss.NewStyle().Bold(true).PaddingLeft(2)
+
+func ParseGroupMode(value string) GroupMode {
+	for _, mode := range GroupModes {
+		if strings.EqualFold(string(mode), strings.TrimSpace(value)) {
+			return mode
+		}
+	}
+	return GroupNone
+}
+
+func (g GroupMode) Next() GroupMode {
+	for i, mode := range GroupModes {
+		if mode == g {
+			return GroupModes[(i+1)%len(GroupModes)]
+		}
+	}
+	return GroupNone
+}
+
+// HeaderItem separates groups in the notes list. It has no filter value so
+// headers drop out of the list while filtering.
+type HeaderItem struct {
+	Label string
+}
+
+func (h HeaderItem) Title() string       { return h.Label }
+func (h HeaderItem) Description() string { return "" }
+func (h HeaderItem) FilterValue() string { return "" }
+
+type notesDelegate struct {
+	list.DefaultDelegate
+}
+
+func (d notesDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if header, ok := item.(HeaderItem); ok {
+		fmt.Fprint(w, groupHeaderStyle.Foreground(theme.CurrentTheme().Muted).Render("── "+header.Label+" ──"))
+		return
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+// BuildGroupedNoteItems puts a header above each year or month of notes.
+// Notes are grouped by the date being sorted on, or by their created date
+// when sorting by title or words, and keep their sorted order within a
+// group. Groups follow the sort direction for date sorts and run newest
+// first otherwise, with undated notes last.
+func BuildGroupedNoteItems(notes []journal.NoteInfo, group GroupMode, sortBy journal.SortField, descending bool) []list.Item {
+	if group == GroupNone {
+		return BuildNoteItems(notes)
+	}
+	type noteGroup struct {
+		label string
+		start time.Time
+		notes []journal.NoteInfo
+	}
+	var groups []*noteGroup
+	byLabel := make(map[string]*noteGroup)
+	for _, note := range notes {
+		label, start := groupOf(note, group, sortBy == journal.SortUpdated)
+		g, ok := byLabel[label]
+		if !ok {
+			g = &noteGroup{label: label, start: start}
+			byLabel[label] = g
+			groups = append(groups, g)
+		}
+		g.notes = append(g.notes, note)
+	}
+	newestFirst := descending || (sortBy != journal.SortUpdated && sortBy != journal.SortCreated)
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].start, groups[j].start
+		if a.IsZero() || b.IsZero() {
+			return b.IsZero() && !a.IsZero()
+		}
+		if newestFirst {
+			return a.After(b)
+		}
+		return a.Before(b)
+	})
+
+	items := make([]list.Item, 0, len(notes)+len(groups))
+	for _, g := range groups {
+		items = append(items, HeaderItem{Label: g.label})
+		for _, note := range g.notes {
+			items = append(items, NoteItem{Info: note})
+		}
+	}
+	return items
+}
+
+// groupOf returns the label of the group a note belongs to and the start
+// of that year or month, which is zero for undated notes.
+func groupOf(note journal.NoteInfo, group GroupMode, byUpdated bool) (string, time.Time) {
+	var when time.Time
+	if byUpdated {
+		when = note.LastUpdated()
+	} else if date, ok := note.Date(); ok {
+		when = date
+	}
+	if when.IsZero() {
+		return "Undated", time.Time{}
+	}
+	when = when.Local()
+	if group == GroupYear {
+		return when.Format("2006"), time.Date(when.Year(), time.January, 1, 0, 0, 0, 0, time.Local)
+	}
+	return when.Format("January 2006"), time.Date(when.Year(), when.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+// SkipHeaders moves the cursor off a group header, preferring the direction
+// the user was already moving in.
+func SkipHeaders(l *list.Model, forward bool) {
+	if _, ok := l.SelectedItem().(HeaderItem); !ok {
+		return
+	}
+	visible := len(l.VisibleItems())
+	for attempt := 0; attempt < 2; attempt++ {
+		for i := 0; i < visible; i++ {
+			previous := l.Index()
+			if forward {
+				l.CursorDown()
+			} else {
+				l.CursorUp()
+			}
+			if _, ok := l.SelectedItem().(HeaderItem); !ok {
+				return
+			}
+			if l.Index() == previous {
+				break
+			}
+		}
+		forward = !forward
+	}
+}
+
+func SelectNote(l *list.Model, filename string) bool {
+	for i, item := range l.Items() {
+		if noteItem, ok := item.(NoteItem); ok && noteItem.Info.Filename == filename {
+			l.Select(i)
+			return true
+		}
+	}
+	return false
+}
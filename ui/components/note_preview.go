@@ -0,0 +1,118 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/never00rei/a7/journal"
+	"github.com/never00rei/a7/ui/theme"
+)
+
+// wordsPerMinute is the reading speed behind the dashboard reading time.
+const wordsPerMinute = 200
+
+var (
+	previewImage    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	previewLink     = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	previewWikiLink = regexp.MustCompile(`\[\[([^\]|]+)(?:\|([^\]]+))?\]\]`)
+	previewHeading  = regexp.MustCompile(`^ {0,3}#{1,6}\s+`)
+	previewQuote    = regexp.MustCompile(`^\s*>\s?`)
+	previewEmphasis = strings.NewReplacer("**", "", "__", "", "`", "", "~~", "")
+)
+
+// ReadingTime formats an estimate such as "3 min read" from a word count.
+func ReadingTime(words int) string {
+	if words < 0 {
+		return "Unavailable"
+	}
+	minutes := (words + wordsPerMinute - 1) / wordsPerMinute
+	if minutes < 1 {
+		minutes = 1
+	}
+	return fmt.Sprintf("%d min read", minutes)
+}
+
+// FormatNotePreview renders a "Preview" section with the start of a note
+// body as plain text, wrapped to width and cut so the section is at most
+// maxLines tall. Encrypted notes that could not be decrypted show a locked
+// placeholder instead.
+func FormatNotePreview(note *journal.Note, loadErr error, loading bool, width, maxLines int) string {
+	muted := lipgloss.NewStyle().Foreground(theme.CurrentTheme().Muted)
+	label := boldLabel("Preview") + "\n"
+	maxLines--
+	switch {
+	case maxLines <= 0 || width <= 0 || (note == nil && !loading):
+		return ""
+	case loading:
+		return label + muted.Render("Loading preview…")
+	case note.Encrypted && loadErr != nil:
+		return label + muted.Width(width).Render("🔒 Locked: this entry can't be decrypted with the current key.")
+	case loadErr != nil:
+		return label + muted.Render("Preview unavailable.")
+	}
+
+	var lines []string
+	for _, line := range strings.Split(previewText(note.Content), "\n") {
+		wrapped := lipgloss.NewStyle().Width(width).Render(line)
+		lines = append(lines, strings.Split(wrapped, "\n")...)
+		if len(lines) > maxLines {
+			break
+		}
+	}
+	if len(lines) == 0 || strings.TrimSpace(strings.Join(lines, "")) == "" {
+		return label + muted.Render("This entry is empty.")
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := strings.TrimRight(lines[maxLines-1], " ")
+		if lipgloss.Width(last) >= width {
+			last = string([]rune(last)[:max(len([]rune(last))-1, 0)])
+		}
+		lines[maxLines-1] = last + "…"
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return label + strings.Join(lines, "\n")
+}
+
+// previewText flattens Markdown so the preview reads as prose: fenced code
+// and blank runs are dropped and link, heading and emphasis syntax removed.
+func previewText(body string) string {
+	var out []string
+	inFence := false
+	blank := true
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if trimmed == "" {
+			if !blank {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		line = previewHeading.ReplaceAllString(line, "")
+		line = previewQuote.ReplaceAllString(line, "")
+		line = previewImage.ReplaceAllString(line, "[$1]")
+		line = previewWikiLink.ReplaceAllStringFunc(line, func(match string) string {
+			parts := previewWikiLink.FindStringSubmatch(match)
+			if parts[2] != "" {
+				return parts[2]
+			}
+			return parts[1]
+		})
+		line = previewLink.ReplaceAllString(line, "$1")
+		out = append(out, previewEmphasis.Replace(strings.TrimRight(line, " ")))
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
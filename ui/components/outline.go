@@ -0,0 +1,59 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/never00rei/a7/journal/codec"
+)
+
+// OutlineItem is a heading in the viewer's jump list. Line is where the
+// heading landed in the rendered note.
+type OutlineItem struct {
+	Heading codec.Heading
+	Line    int
+}
+
+func (o OutlineItem) Title() string {
+	return strings.Repeat("  ", o.Heading.Level-1) + o.Heading.Text
+}
+
+func (o OutlineItem) Description() string { return "" }
+
+func (o OutlineItem) FilterValue() string { return o.Heading.Text }
+
+// BuildOutlineItems pairs each heading with its line in the rendered
+// content, searching forward so repeated headings map in order.
+func BuildOutlineItems(headings []codec.Heading, rendered string) []list.Item {
+	lines := strings.Split(rendered, "\n")
+	items := make([]list.Item, 0, len(headings))
+	plain := strings.NewReplacer("*", "", "_", "", "`", "")
+	next := 0
+	for _, heading := range headings {
+		text := plain.Replace(heading.Text)
+		line := next
+		for i := next; i < len(lines); i++ {
+			if strings.Contains(ansi.Strip(lines[i]), text) {
+				line = i
+				next = i + 1
+				break
+			}
+		}
+		items = append(items, OutlineItem{Heading: heading, Line: line})
+	}
+	return items
+}
+
+func NewOutlineList(width, height int) list.Model {
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+	delegate.SetSpacing(0)
+	l := list.New(nil, delegate, width, height)
+	l.SetShowHelp(false)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(true)
+	l.DisableQuitKeybindings()
+	return l
+}
@@ -0,0 +1,72 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/never00rei/a7/ui/theme"
+)
+
+// PaletteItem is a command palette row. Matched holds the byte offsets of
+// the runes in Title that matched the query so they can be highlighted.
+type PaletteItem struct {
+	Title   string
+	Hint    string
+	Matched []int
+}
+
+// FormatPalette renders the query input above the matching commands, with
+// the selected command reversed.
+func FormatPalette(input string, items []PaletteItem, selected, width int) string {
+	var b strings.Builder
+	b.WriteString(input)
+	b.WriteString("\n\n")
+	if len(items) == 0 {
+		b.WriteString(lipgloss.NewStyle().Foreground(theme.CurrentTheme().Muted).Render("No matching commands"))
+		return b.String()
+	}
+	matchStyle := lipgloss.NewStyle().Foreground(theme.CurrentTheme().Accent).Bold(true)
+	hintStyle := lipgloss.NewStyle().Foreground(theme.CurrentTheme().Muted)
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		title := highlightMatches(item.Title, item.Matched, matchStyle)
+		gap := width - lipgloss.Width(item.Title) - lipgloss.Width(item.Hint) - 2
+		if gap < 1 {
+			gap = 1
+		}
+		row := fmt.Sprintf(" %s%s%s ", title, strings.Repeat(" ", gap), hintStyle.Render(item.Hint))
+		if i == selected {
+			row = lipgloss.NewStyle().Reverse(true).Render(fmt.Sprintf(" %s%s%s ", item.Title, strings.Repeat(" ", gap), item.Hint))
+		}
+		b.WriteString(row)
+	}
+	return b.String()
+}
+
+// FormatPaletteArg renders the input for a command that needs a value.
+func FormatPaletteArg(title, prompt, input string) string {
+	muted := lipgloss.NewStyle().Foreground(theme.CurrentTheme().Muted)
+	return boldLabel(title) + "\n" + muted.Render(prompt) + "\n\n" + input
+}
+
+func highlightMatches(text string, indexes []int, style lipgloss.Style) string {
+	if len(indexes) == 0 {
+		return text
+	}
+	matched := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		matched[i] = true
+	}
+	var b strings.Builder
+	for i, r := range text {
+		if matched[i] {
+			b.WriteString(style.Render(string(r)))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
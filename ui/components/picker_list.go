@@ -0,0 +1,14 @@
+package components
+
+import "github.com/charmbracelet/bubbles/list"
+
+func NewPickerList(items []list.Item, width, height int) list.Model {
+	l := list.New(items, list.NewDefaultDelegate(), width, height)
+	l.SetShowHelp(false)
+	l.SetShowPagination(true)
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(true)
+	l.DisableQuitKeybindings()
+	l.Title = ""
+	return l
+}
@@ -0,0 +1,42 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/never00rei/a7/journal/prompts"
+)
+
+type PromptCategoryItem struct {
+	Category string
+	Count    int
+}
+
+func (p PromptCategoryItem) Title() string {
+	return p.Category
+}
+
+func (p PromptCategoryItem) Description() string {
+	if p.Category == prompts.AnyCategory {
+		return fmt.Sprintf("A random prompt from all %d", p.Count)
+	}
+	return fmt.Sprintf("%d prompts", p.Count)
+}
+
+func (p PromptCategoryItem) FilterValue() string {
+	return p.Category
+}
+
+func BuildPromptCategoryItems(available []prompts.Prompt) []list.Item {
+	counts := map[string]int{}
+	for _, prompt := range available {
+		counts[prompt.Category]++
+	}
+	categories := prompts.Categories(available)
+	items := make([]list.Item, 0, len(categories)+1)
+	items = append(items, PromptCategoryItem{Category: prompts.AnyCategory, Count: len(available)})
+	for _, category := range categories {
+		items = append(items, PromptCategoryItem{Category: category, Count: counts[category]})
+	}
+	return items
+}
@@ -0,0 +1,128 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/never00rei/a7/journal/stats"
+)
+
+const (
+	heatmapCell   = "■"
+	maxBarWidth   = 30
+	heatmapLabelW = 4
+)
+
+var heatmapLevels = []lipgloss.Color{"237", "22", "28", "34", "40"}
+
+func FormatStats(summary stats.Summary, width int, now time.Time) string {
+	sections := []string{
+		boldLabel("Streaks"),
+		fmt.Sprintf("Current: %s   Longest: %s", pluralDays(summary.CurrentStreak), pluralDays(summary.LongestStreak)),
+		"",
+		boldLabel("Totals"),
+		fmt.Sprintf("%d entries • %d words • %.0f words per entry on average", summary.TotalEntries, summary.TotalWords, summary.AverageWords),
+		"",
+		boldLabel("Activity"),
+		RenderHeatmap(summary.ActivityByDay(), now, heatmapWeeks(width)),
+		"",
+		boldLabel("Last 12 weeks"),
+		renderPeriods(summary.Weekly, "2006-01-02"),
+		"",
+		boldLabel("Last 12 months"),
+		renderPeriods(summary.Monthly, "Jan 2006"),
+		"",
+		boldLabel("Most used tags"),
+		renderTags(summary.TopTags),
+	}
+	return strings.Join(sections, "\n")
+}
+
+// RenderHeatmap draws one column per week and one row per weekday, shading
+// each day by how many entries were written.
+func RenderHeatmap(activity map[string]int, now time.Time, weeks int) string {
+	if weeks < 1 {
+		weeks = 1
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	offset := (int(today.Weekday()) + 6) % 7
+	start := today.AddDate(0, 0, -offset-7*(weeks-1))
+
+	labels := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	rows := make([]string, 7)
+	for weekday := 0; weekday < 7; weekday++ {
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("%-*s", heatmapLabelW, labels[weekday]))
+		for week := 0; week < weeks; week++ {
+			date := start.AddDate(0, 0, week*7+weekday)
+			if date.After(today) {
+				b.WriteString("  ")
+				continue
+			}
+			level := heatmapLevel(activity[date.Format(stats.DayLayout)])
+			b.WriteString(lipgloss.NewStyle().Foreground(heatmapLevels[level]).Render(heatmapCell))
+			b.WriteString(" ")
+		}
+		rows[weekday] = strings.TrimRight(b.String(), " ")
+	}
+	return strings.Join(rows, "\n")
+}
+
+func heatmapWeeks(width int) int {
+	weeks := (width - heatmapLabelW) / 2
+	if weeks > 53 {
+		return 53
+	}
+	if weeks < 4 {
+		return 4
+	}
+	return weeks
+}
+
+func heatmapLevel(entries int) int {
+	if entries <= 0 {
+		return 0
+	}
+	if entries >= len(heatmapLevels)-1 {
+		return len(heatmapLevels) - 1
+	}
+	return entries
+}
+
+func renderPeriods(periods []stats.Period, layout string) string {
+	most := 0
+	for _, period := range periods {
+		if period.Entries > most {
+			most = period.Entries
+		}
+	}
+	lines := make([]string, 0, len(periods))
+	for _, period := range periods {
+		bar := ""
+		if most > 0 {
+			bar = strings.Repeat("█", period.Entries*maxBarWidth/most)
+		}
+		lines = append(lines, fmt.Sprintf("%-10s %-*s %d entries, %d words", period.Start.Format(layout), maxBarWidth, bar, period.Entries, period.Words))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderTags(tags []stats.TagCount) string {
+	if len(tags) == 0 {
+		return "No tags yet. Add #hashtags to your entries."
+	}
+	parts := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		parts = append(parts, fmt.Sprintf("#%s (%d)", tag.Tag, tag.Count))
+	}
+	return strings.Join(parts, "  ")
+}
+
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
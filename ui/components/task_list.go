@@ -0,0 +1,43 @@
+package components
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/never00rei/a7/journal"
+)
+
+type TaskItem struct {
+	Task journal.Task
+}
+
+func (t TaskItem) Title() string {
+	if t.Task.Done {
+		return "[x] " + t.Task.Text
+	}
+	return "[ ] " + t.Task.Text
+}
+
+func (t TaskItem) Description() string {
+	title := t.Task.Note.Title
+	if title == "" {
+		title = journal.NoteID(t.Task.Note.Filename)
+	}
+	if date, ok := t.Task.Note.Date(); ok {
+		return title + " · " + date.Local().Format("Mon 2 Jan 2006")
+	}
+	return title
+}
+
+func (t TaskItem) FilterValue() string {
+	return t.Task.Text + " " + t.Task.Note.Title
+}
+
+func BuildTaskItems(tasks []journal.Task, showDone bool) []list.Item {
+	items := make([]list.Item, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Done && !showDone {
+			continue
+		}
+		items = append(items, TaskItem{Task: task})
+	}
+	return items
+}
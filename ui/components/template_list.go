@@ -0,0 +1,39 @@
+package components
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/never00rei/a7/journal/templates"
+)
+
+const BlankTemplateName = "blank"
+
+type TemplateItem struct {
+	Template templates.Template
+}
+
+func (t TemplateItem) Title() string {
+	return t.Template.Name
+}
+
+func (t TemplateItem) Description() string {
+	if t.Template.Name == BlankTemplateName {
+		return "Start from an empty note"
+	}
+	if t.Template.Title != "" {
+		return t.Template.Title
+	}
+	return "Untitled template"
+}
+
+func (t TemplateItem) FilterValue() string {
+	return t.Template.Name
+}
+
+func BuildTemplateItems(available []templates.Template) []list.Item {
+	items := make([]list.Item, 0, len(available)+1)
+	items = append(items, TemplateItem{Template: templates.Template{Name: BlankTemplateName}})
+	for _, tmpl := range available {
+		items = append(items, TemplateItem{Template: tmpl})
+	}
+	return items
+}
@@ -0,0 +1,340 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+const (
+	PresetDefault = "default"
+	PresetVim     = "vim"
+	PresetEmacs   = "emacs"
+)
+
+type KeyMap struct {
+	Up    key.Binding
+	Down  key.Binding
+	Left  key.Binding
+	Right key.Binding
+	Open  key.Binding
+	Back  key.Binding
+	Quit  key.Binding
+	Help  key.Binding
+	// Palette opens the command palette.
+	Palette key.Binding
+
+	New      key.Binding
+	Prompt   key.Binding
+	Calendar key.Binding
+	Stats    key.Binding
+	Tasks    key.Binding
+	Sort     key.Binding
+	Reverse  key.Binding
+	Group    key.Binding
+	Edit     key.Binding
+	Settings key.Binding
+
+	Save      key.Binding
+	Preview   key.Binding
+	NextField key.Binding
+	PrevField key.Binding
+	// DiscardDraft throws away an autosaved draft offered for recovery, or
+	// the unsaved changes when leaving the editor.
+	DiscardDraft key.Binding
+
+	NextLink   key.Binding
+	Attachment key.Binding
+	NextEntry  key.Binding
+	PrevEntry  key.Binding
+	Find       key.Binding
+	NextMatch  key.Binding
+	PrevMatch  key.Binding
+	Outline    key.Binding
+	ToggleTask key.Binding
+	ShowDone   key.Binding
+
+	PrevMonth key.Binding
+	NextMonth key.Binding
+	PrevYear  key.Binding
+	NextYear  key.Binding
+	Today     key.Binding
+	DayList   key.Binding
+}
+
+// Group is a titled set of bindings for the full help overlay.
+type Group struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+func Default() KeyMap {
+	return KeyMap{
+		Up:      bind("up", "up", "k"),
+		Down:    bind("down", "down", "j"),
+		Left:    bind("left", "left", "h"),
+		Right:   bind("right", "right", "l"),
+		Open:    bind("open", "enter"),
+		Back:    bind("back", "esc"),
+		Quit:    bind("quit", "ctrl+c"),
+		Help:    bind("help", "?"),
+		Palette: bind("commands", "ctrl+p"),
+
+		New:      bind("new", "n"),
+		Prompt:   bind("prompt", "p"),
+		Calendar: bind("calendar", "c"),
+		Stats:    bind("stats", "i"),
+		Tasks:    bind("tasks", "t"),
+		Sort:     bind("sort", "o"),
+		Reverse:  bind("reverse", "r"),
+		Group:    bind("group", "y"),
+		Edit:     bind("edit", "e"),
+		Settings: bind("settings", "s"),
+
+		Save:      bind("save", "ctrl+s"),
+		Preview:   bind("preview", "ctrl+r"),
+		NextField: bind("switch", "tab"),
+		PrevField: bind("switch back", "shift+tab"),
+
+		DiscardDraft: bind("discard draft", "x"),
+
+		NextLink:   bind("select link", "tab"),
+		Attachment: key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp("1-9", "open attachment")),
+		NextEntry:  bind("next entry", "]"),
+		PrevEntry:  bind("previous entry", "["),
+		Find:       bind("find", "/"),
+		NextMatch:  bind("next match", "n"),
+		PrevMatch:  bind("previous match", "N"),
+		Outline:    bind("outline", "o"),
+		ToggleTask: bind("toggle", " "),
+		ShowDone:   bind("show done", "a"),
+
+		PrevMonth: bind("previous month", "[", "pgup"),
+		NextMonth: bind("next month", "]", "pgdown"),
+		PrevYear:  bind("previous year", "{"),
+		NextYear:  bind("next year", "}"),
+		Today:     bind("today", "t"),
+		DayList:   bind("day list", "tab"),
+	}
+}
+
+// Preset returns the named keymap. The vim preset adds q to go back and
+// ctrl+u/ctrl+d paging; the emacs preset moves with ctrl+p/n/b/f, backs out
+// with ctrl+g and opens the command palette with alt+x.
+func Preset(name string) (KeyMap, error) {
+	keys := Default()
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", PresetDefault:
+	case PresetVim:
+		rebind(&keys.Back, "esc", "q")
+		rebind(&keys.PrevMonth, "[", "ctrl+u", "pgup")
+		rebind(&keys.NextMonth, "]", "ctrl+d", "pgdown")
+	case PresetEmacs:
+		rebind(&keys.Up, "ctrl+p", "up")
+		rebind(&keys.Down, "ctrl+n", "down")
+		rebind(&keys.Left, "ctrl+b", "left")
+		rebind(&keys.Right, "ctrl+f", "right")
+		rebind(&keys.Back, "esc", "ctrl+g")
+		rebind(&keys.Palette, "alt+x")
+		rebind(&keys.PrevMonth, "alt+v", "pgup")
+		rebind(&keys.NextMonth, "ctrl+v", "pgdown")
+		rebind(&keys.PrevYear, "alt+<", "{")
+		rebind(&keys.NextYear, "alt+>", "}")
+	default:
+		return keys, fmt.Errorf("unknown key preset %q", name)
+	}
+	return keys, nil
+}
+
+// Load builds the keymap from a preset and per-action overrides such as
+// save = "ctrl+s, ctrl+w". Invalid entries are reported but skipped, so the
+// returned keymap is always usable.
+func Load(preset string, overrides map[string]string) (KeyMap, error) {
+	keys, err := Preset(preset)
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	bindings := keys.named()
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		binding, ok := bindings[strings.ToLower(name)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown key action %q", name))
+			continue
+		}
+		values := ParseKeys(overrides[name])
+		if len(values) == 0 {
+			errs = append(errs, fmt.Errorf("no keys set for %q", name))
+			continue
+		}
+		rebind(binding, values...)
+	}
+	return keys, errors.Join(errs...)
+}
+
+// ParseKeys splits a comma separated key list; "space" stands for the space
+// bar.
+func ParseKeys(value string) []string {
+	var keys []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if part == "space" {
+			part = " "
+		}
+		keys = append(keys, part)
+	}
+	return keys
+}
+
+func (k *KeyMap) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":          &k.Up,
+		"down":        &k.Down,
+		"left":        &k.Left,
+		"right":       &k.Right,
+		"open":        &k.Open,
+		"back":        &k.Back,
+		"quit":        &k.Quit,
+		"help":        &k.Help,
+		"palette":     &k.Palette,
+		"new":         &k.New,
+		"prompt":      &k.Prompt,
+		"calendar":    &k.Calendar,
+		"stats":       &k.Stats,
+		"tasks":       &k.Tasks,
+		"sort":        &k.Sort,
+		"reverse":     &k.Reverse,
+		"group":       &k.Group,
+		"edit":        &k.Edit,
+		"settings":    &k.Settings,
+		"save":        &k.Save,
+		"preview":     &k.Preview,
+		"next_field":  &k.NextField,
+		"prev_field":  &k.PrevField,
+		"discard":     &k.DiscardDraft,
+		"next_link":   &k.NextLink,
+		"next_entry":  &k.NextEntry,
+		"prev_entry":  &k.PrevEntry,
+		"find":        &k.Find,
+		"next_match":  &k.NextMatch,
+		"prev_match":  &k.PrevMatch,
+		"outline":     &k.Outline,
+		"toggle_task": &k.ToggleTask,
+		"show_done":   &k.ShowDone,
+		"prev_month":  &k.PrevMonth,
+		"next_month":  &k.NextMonth,
+		"prev_year":   &k.PrevYear,
+		"next_year":   &k.NextYear,
+		"today":       &k.Today,
+		"day_list":    &k.DayList,
+	}
+}
+
+// ForTyping returns the keymap to use while a text field has focus. Back
+// and Quit lose their single character keys, such as the vim preset's q, so
+// those characters can be typed.
+func (k KeyMap) ForTyping() KeyMap {
+	k.Back = withoutCharacters(k.Back)
+	k.Quit = withoutCharacters(k.Quit)
+	return k
+}
+
+func withoutCharacters(b key.Binding) key.Binding {
+	var kept []string
+	for _, k := range b.Keys() {
+		if utf8.RuneCountInString(k) != 1 {
+			kept = append(kept, k)
+		}
+	}
+	if len(kept) != len(b.Keys()) {
+		rebind(&b, kept...)
+	}
+	return b
+}
+
+// ApplyToList points a list's cursor movement at the keymap's up and down
+// bindings.
+func (k KeyMap) ApplyToList(l *list.Model) {
+	l.KeyMap.CursorUp = k.Up
+	l.KeyMap.CursorDown = k.Down
+}
+
+func (k KeyMap) ApplyToViewport(v *viewport.Model) {
+	v.KeyMap.Up = k.Up
+	v.KeyMap.Down = k.Down
+}
+
+// FullHelp groups every binding by the screen it applies to.
+func (k KeyMap) FullHelp() []Group {
+	return []Group{
+		{Title: "Global", Bindings: []key.Binding{k.Up, k.Down, k.Open, k.Back, k.Palette, k.Help, k.Quit}},
+		{Title: "Dashboard", Bindings: []key.Binding{k.New, k.Prompt, k.Edit, k.Calendar, k.Stats, k.Tasks, k.Sort, k.Reverse, k.Group, k.Settings}},
+		{Title: "Viewer", Bindings: []key.Binding{k.NextEntry, k.PrevEntry, k.Find, k.NextMatch, k.PrevMatch, k.Outline, k.NextLink, k.Attachment, k.Edit}},
+		{Title: "Editor", Bindings: []key.Binding{k.Save, k.Preview, k.NextField, k.PrevField}},
+		{Title: "Calendar", Bindings: []key.Binding{k.Left, k.Right, k.PrevMonth, k.NextMonth, k.PrevYear, k.NextYear, k.Today, k.DayList}},
+		{Title: "Tasks", Bindings: []key.Binding{k.ToggleTask, k.ShowDone}},
+	}
+}
+
+// Describe returns a copy of b with its help text reworded for one screen.
+func Describe(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// HelpLine renders bindings as a footer line such as "↑/k up • esc back".
+func HelpLine(bindings ...key.Binding) string {
+	parts := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		help := b.Help()
+		parts = append(parts, help.Key+" "+help.Desc)
+	}
+	return strings.Join(parts, " • ")
+}
+
+func bind(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKey(keys), desc))
+}
+
+func rebind(b *key.Binding, keys ...string) {
+	desc := b.Help().Desc
+	b.SetKeys(keys...)
+	b.SetHelp(helpKey(keys), desc)
+}
+
+func helpKey(keys []string) string {
+	labels := make([]string, 0, len(keys))
+	for _, k := range keys {
+		switch k {
+		case "up":
+			k = "↑"
+		case "down":
+			k = "↓"
+		case "left":
+			k = "←"
+		case "right":
+			k = "→"
+		case " ":
+			k = "space"
+		}
+		labels = append(labels, k)
+	}
+	return strings.Join(labels, "/")
+}
@@ -0,0 +1,38 @@
+package keys
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLoadAppliesPresetAndOverrides(t *testing.T) {
+	keys, err := Load(PresetEmacs, map[string]string{"save": "ctrl+w, ctrl+s", "toggle_task": "space, x"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlN}, keys.Down) {
+		t.Fatalf("emacs preset should move down with ctrl+n")
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlW}, keys.Save) || keys.Save.Help().Key != "ctrl+w/ctrl+s" {
+		t.Fatalf("save override = %v", keys.Save.Keys())
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}, keys.ToggleTask) {
+		t.Fatalf("space alias not applied: %q", keys.ToggleTask.Keys())
+	}
+	if got := HelpLine(keys.Save, keys.ToggleTask); got != "ctrl+w/ctrl+s save • space/x toggle" {
+		t.Fatalf("HelpLine = %q", got)
+	}
+}
+
+func TestLoadReportsUnknownSettings(t *testing.T) {
+	keys, err := Load("nano", map[string]string{"teleport": "ctrl+t"})
+	if err == nil || !strings.Contains(err.Error(), "nano") || !strings.Contains(err.Error(), "teleport") {
+		t.Fatalf("err = %v, want unknown preset and action", err)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlS}, keys.Save) {
+		t.Fatalf("defaults not kept after errors")
+	}
+}
@@ -70,6 +70,10 @@ func (l Layout) TwoPaneWithRatioAndTitles(leftTitle, rightTitle, left, right str
 }
 
 func (l Layout) TwoPaneWithRatioAndTitlesAndWidth(leftTitle, rightTitle, left, right string, leftRatio float64, totalWidth int) string {
+	return l.TwoPaneWithRatioAndTitlesAndSize(leftTitle, rightTitle, left, right, leftRatio, totalWidth, l.BodyHeight())
+}
+
+func (l Layout) TwoPaneWithRatioAndTitlesAndSize(leftTitle, rightTitle, left, right string, leftRatio float64, totalWidth, totalHeight int) string {
 	theme := l.ActiveTheme
 	total := totalWidth
 	if total <= 0 {
@@ -83,7 +87,7 @@ func (l Layout) TwoPaneWithRatioAndTitlesAndWidth(leftTitle, rightTitle, left, r
 	if available < 0 {
 		available = 0
 	}
-	availableHeight := l.BodyHeight()
+	availableHeight := totalHeight
 	if leftRatio < 0 {
 		leftRatio = 0
 	}
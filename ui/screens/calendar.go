@@ -0,0 +1,17 @@
+package screens
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/never00rei/a7/ui/layout"
+)
+
+const CalendarLeftRatio = 0.5
+
+func Calendar(layout layout.Layout, grid string, dayTitle string, dayList list.Model, dayCount int) string {
+	right := dayList.View()
+	if dayCount == 0 {
+		right = "No journals on this day."
+	}
+	body := layout.TwoPaneWithRatioAndTitlesAndWidth("Calendar", dayTitle, grid, right, CalendarLeftRatio, layout.ContentWidth())
+	return layout.CenterContent(body)
+}
@@ -2,12 +2,13 @@ package screens
 
 import (
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/never00rei/a7/journal"
 	"github.com/never00rei/a7/ui/components"
 	"github.com/never00rei/a7/ui/layout"
 )
 
-func Dashboard(layout layout.Layout, storagePath string, dashboardErr error, notes []journal.NoteInfo, notesList list.Model, dashboardNote *journal.Note, dashboardNoteErr error) string {
+func Dashboard(layout layout.Layout, storagePath string, dashboardErr error, notes []journal.NoteInfo, notesList list.Model, dashboardNote *journal.Note, dashboardNoteErr error, loading bool, attachments int, status string) string {
 	if storagePath == "" {
 		bodyText := "Set a journal folder to see recent entries.\n" +
 			"Run setup to choose a storage location."
@@ -26,7 +27,15 @@ func Dashboard(layout layout.Layout, storagePath string, dashboardErr error, not
 	if len(notes) == 0 {
 		left = "No journals yet.\nCreate your first entry."
 	}
-	right := components.FormatSelectedMeta(notesList.SelectedItem(), len(notes), dashboardNote, dashboardNoteErr)
+	right := components.FormatSelectedMeta(notesList.SelectedItem(), len(notes), dashboardNote, dashboardNoteErr, loading, attachments)
+	if status != "" {
+		right = components.StatusStyle().Render(status) + "\n\n" + right
+	}
+	_, rightWidth := layout.SplitPaneContentWidths(components.DashboardLeftRatio)
+	remaining := layout.PaneContentHeight(layout.BodyHeight()) - lipgloss.Height(right) - 1
+	if preview := components.FormatNotePreview(dashboardNote, dashboardNoteErr, loading, rightWidth, remaining); preview != "" {
+		right += "\n\n" + preview
+	}
 	body := layout.TwoPaneWithRatioAndTitlesAndWidth("Saved Journals", "Journal Metadata", left, right, components.DashboardLeftRatio, layout.ContentWidth())
 	return layout.CenterContent(body)
 }
@@ -7,7 +7,9 @@ import (
 	"github.com/never00rei/a7/ui/layout"
 )
 
-func Editor(layout layout.Layout, titleView string, bodyView string, editorErr error) string {
+const EditorPreviewRatio = 0.5
+
+func Editor(layout layout.Layout, titleView string, bodyView string, previewView string, showPreview bool, editorErr error) string {
 	bodyParts := []string{bodyView}
 	if editorErr != nil {
 		bodyParts = append(bodyParts, "", "Error: "+editorErr.Error())
@@ -22,6 +24,9 @@ func Editor(layout layout.Layout, titleView string, bodyView string, editorErr e
 		bodyPaneHeight = 3
 	}
 	bodyPane := layout.TitledPaneWithWidthAndHeight("Journal", bodyContent, width, bodyPaneHeight)
+	if showPreview {
+		bodyPane = layout.TwoPaneWithRatioAndTitlesAndSize("Journal", "Preview", bodyContent, previewView, EditorPreviewRatio, width, bodyPaneHeight)
+	}
 
 	content := titlePane + "\n" + bodyPane
 	return layout.CenterContent(content)
@@ -0,0 +1,8 @@
+package screens
+
+import "github.com/never00rei/a7/ui/layout"
+
+func Help(layout layout.Layout, helpContent string) string {
+	pane := layout.TitledPaneWithWidth("Keys", helpContent, layout.ContentWidth())
+	return layout.CenterContent(pane)
+}
@@ -0,0 +1,17 @@
+package screens
+
+import "github.com/never00rei/a7/ui/layout"
+
+// PaletteWidth is the outer width of the command palette pane.
+func PaletteWidth(layout layout.Layout) int {
+	width := layout.ContentWidth() * 2 / 3
+	if width < 40 {
+		width = min(40, layout.ContentWidth())
+	}
+	return width
+}
+
+func Palette(layout layout.Layout, content string) string {
+	pane := layout.TitledPaneWithWidth("Commands", content, PaletteWidth(layout))
+	return layout.CenterContent(pane)
+}
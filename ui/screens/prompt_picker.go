@@ -0,0 +1,15 @@
+package screens
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/never00rei/a7/ui/layout"
+)
+
+func PromptPicker(layout layout.Layout, categoryList list.Model, pickerErr error) string {
+	content := categoryList.View()
+	if pickerErr != nil {
+		content = content + "\n\nError: " + pickerErr.Error()
+	}
+	pane := layout.TitledPaneWithWidth("New Journal From Prompt", content, layout.PrimaryPaneWidth())
+	return layout.CenterContent(pane)
+}
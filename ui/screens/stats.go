@@ -0,0 +1,8 @@
+package screens
+
+import "github.com/never00rei/a7/ui/layout"
+
+func Stats(layout layout.Layout, statsContent string) string {
+	pane := layout.TitledPaneWithWidth("Writing Stats", statsContent, layout.ContentWidth())
+	return layout.CenterContent(pane)
+}
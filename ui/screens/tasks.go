@@ -0,0 +1,22 @@
+package screens
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/never00rei/a7/ui/layout"
+)
+
+func Tasks(layout layout.Layout, taskList list.Model, showDone bool, tasksErr error) string {
+	title := "Open Tasks"
+	if showDone {
+		title = "All Tasks"
+	}
+	content := taskList.View()
+	if len(taskList.Items()) == 0 {
+		content = "No open tasks.\nAdd `- [ ] something` to an entry to track it here."
+	}
+	if tasksErr != nil {
+		content = content + "\n\nError: " + tasksErr.Error()
+	}
+	pane := layout.TitledPaneWithWidth(title, content, layout.PrimaryPaneWidth())
+	return layout.CenterContent(pane)
+}
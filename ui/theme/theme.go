@@ -1,19 +1,219 @@
 package theme
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	Auto         = "auto"
+	Dark         = "dark"
+	Light        = "light"
+	HighContrast = "high-contrast"
+)
 
 type Theme struct {
+	Name        string
 	FrameBorder lipgloss.Color
 	PaneBorder  lipgloss.Color
 	Text        lipgloss.Color
 	Help        lipgloss.Color
+	Accent      lipgloss.Color
+	Muted       lipgloss.Color
+	// Markdown is a glamour style name or the path to a glamour JSON style.
+	Markdown string
 }
 
-func CurrentTheme() Theme {
-	return Theme{
+var builtins = map[string]Theme{
+	Dark: {
+		Name:        Dark,
 		FrameBorder: lipgloss.Color("240"),
 		PaneBorder:  lipgloss.Color("245"),
 		Text:        lipgloss.Color("252"),
 		Help:        lipgloss.Color("244"),
+		Accent:      lipgloss.Color("78"),
+		Muted:       lipgloss.Color("245"),
+		Markdown:    "dark",
+	},
+	Light: {
+		Name:        Light,
+		FrameBorder: lipgloss.Color("249"),
+		PaneBorder:  lipgloss.Color("243"),
+		Text:        lipgloss.Color("235"),
+		Help:        lipgloss.Color("241"),
+		Accent:      lipgloss.Color("28"),
+		Muted:       lipgloss.Color("242"),
+		Markdown:    "light",
+	},
+	HighContrast: {
+		Name:        HighContrast,
+		FrameBorder: lipgloss.Color("15"),
+		PaneBorder:  lipgloss.Color("15"),
+		Text:        lipgloss.Color("15"),
+		Help:        lipgloss.Color("229"),
+		Accent:      lipgloss.Color("51"),
+		Muted:       lipgloss.Color("252"),
+		Markdown:    "dark",
+	},
+}
+
+var (
+	mu      sync.RWMutex
+	current = builtins[Dark]
+)
+
+func CurrentTheme() Theme {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+func SetCurrent(t Theme) {
+	mu.Lock()
+	current = t
+	mu.Unlock()
+}
+
+// Builtin returns a copy of the named built-in theme.
+func Builtin(name string) (Theme, bool) {
+	t, ok := builtins[name]
+	return t, ok
+}
+
+// Names lists auto, the built-in themes and the given user themes, in the
+// order the settings form offers them.
+func Names(user []Theme) []string {
+	names := []string{Auto, Dark, Light, HighContrast}
+	for _, t := range user {
+		if _, ok := builtins[t.Name]; ok || t.Name == Auto {
+			continue
+		}
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// Resolve picks the theme called name, preferring user themes over built-in
+// ones. Auto, empty and unknown names follow the terminal background, which
+// is only queried in that case.
+func Resolve(name string, user []Theme, darkBackground func() bool) Theme {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, t := range user {
+		if t.Name == name {
+			return t
+		}
+	}
+	if t, ok := builtins[name]; ok {
+		return t
+	}
+	if darkBackground() {
+		return builtins[Dark]
 	}
+	return builtins[Light]
+}
+
+type themeFile struct {
+	Name        string `yaml:"name"`
+	Base        string `yaml:"base"`
+	FrameBorder string `yaml:"frame_border"`
+	PaneBorder  string `yaml:"pane_border"`
+	Text        string `yaml:"text"`
+	Help        string `yaml:"help"`
+	Accent      string `yaml:"accent"`
+	Muted       string `yaml:"muted"`
+	Markdown    string `yaml:"markdown"`
+}
+
+// Load reads user themes from YAML files in dir. Each theme starts from its
+// base built-in theme (dark by default) and overrides the colours it sets; a
+// markdown value that is not a glamour style name is read as a JSON style
+// file relative to dir.
+func Load(dir string) ([]Theme, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list themes: %w", err)
+	}
+
+	var themes []Theme
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("load theme: %w", err)
+		}
+		t, err := Parse(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())), content)
+		if err != nil {
+			return nil, fmt.Errorf("load theme %s: %w", entry.Name(), err)
+		}
+		if t.Markdown != "" && !isGlamourStyle(t.Markdown) && !filepath.IsAbs(t.Markdown) {
+			t.Markdown = filepath.Join(dir, t.Markdown)
+		}
+		themes = append(themes, t)
+	}
+	sort.Slice(themes, func(i, j int) bool {
+		return themes[i].Name < themes[j].Name
+	})
+	return themes, nil
+}
+
+func Parse(name string, content []byte) (Theme, error) {
+	var file themeFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return Theme{}, err
+	}
+	base := builtins[Dark]
+	if file.Base != "" {
+		b, ok := builtins[strings.ToLower(file.Base)]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown base theme %q", file.Base)
+		}
+		base = b
+	}
+	if file.Name != "" {
+		name = file.Name
+	}
+	base.Name = strings.ToLower(strings.TrimSpace(name))
+	override(&base.FrameBorder, file.FrameBorder)
+	override(&base.PaneBorder, file.PaneBorder)
+	override(&base.Text, file.Text)
+	override(&base.Help, file.Help)
+	override(&base.Accent, file.Accent)
+	override(&base.Muted, file.Muted)
+	if file.Markdown != "" {
+		base.Markdown = file.Markdown
+	}
+	return base, nil
+}
+
+func override(c *lipgloss.Color, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		*c = lipgloss.Color(value)
+	}
+}
+
+// glamourStyles mirrors glamour's standard style names so the theme package
+// does not need to depend on glamour.
+var glamourStyles = map[string]bool{
+	"ascii": true, "auto": true, "dark": true, "dracula": true, "light": true,
+	"notty": true, "pink": true, "tokyo-night": true,
+}
+
+func isGlamourStyle(name string) bool {
+	return glamourStyles[name]
 }
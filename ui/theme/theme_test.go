@@ -0,0 +1,59 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestLoadReadsUserThemesOverBase(t *testing.T) {
+	dir := t.TempDir()
+	content := "base: light\naccent: \"#268bd2\"\nmarkdown: solarized.json\n"
+	if err := os.WriteFile(filepath.Join(dir, "Solarized.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("write theme: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	themes, err := Load(dir)
+	if err != nil || len(themes) != 1 {
+		t.Fatalf("Load = %v, %v", themes, err)
+	}
+	got := themes[0]
+	light, _ := Builtin(Light)
+	if got.Name != "solarized" || got.Accent != lipgloss.Color("#268bd2") || got.Text != light.Text {
+		t.Fatalf("theme = %+v", got)
+	}
+	if got.Markdown != filepath.Join(dir, "solarized.json") {
+		t.Fatalf("markdown = %q, want path in theme dir", got.Markdown)
+	}
+	if names := Names(themes); names[len(names)-1] != "solarized" || names[0] != Auto {
+		t.Fatalf("Names = %v", names)
+	}
+}
+
+func TestResolveFollowsBackgroundForAuto(t *testing.T) {
+	detected := false
+	dark := func() bool {
+		detected = true
+		return false
+	}
+	if got := Resolve(HighContrast, nil, dark); got.Name != HighContrast || detected {
+		t.Fatalf("Resolve(high-contrast) = %q, detected=%v", got.Name, detected)
+	}
+	if got := Resolve(Auto, nil, dark); got.Name != Light || !detected {
+		t.Fatalf("Resolve(auto) on light background = %q", got.Name)
+	}
+	if got := Resolve("missing", nil, func() bool { return true }); got.Name != Dark {
+		t.Fatalf("Resolve(missing) = %q, want dark", got.Name)
+	}
+}
+
+func TestParseRejectsUnknownBase(t *testing.T) {
+	if _, err := Parse("bad", []byte("base: sepia\n")); err == nil {
+		t.Fatalf("expected error for unknown base")
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"time"
 )
 
@@ -87,3 +89,22 @@ func SanitizeSpecialChars(input string) string {
 	result := re.ReplaceAllString(input, "_")
 	return result
 }
+
+// OpenWithSystem opens path with the desktop's default application without
+// waiting for it to exit.
+func OpenWithSystem(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("could not open file: %w", err)
+	}
+	go cmd.Wait()
+	return nil
+}